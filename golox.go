@@ -1,31 +1,65 @@
 package main
 
 import (
+	"flag"
 	"fmt"
 	"io"
 	"io/ioutil"
 	"os"
 	"path/filepath"
+	"strings"
 
 	"github.com/aliwalker/golox/lox"
 	"github.com/chzyer/readline"
 )
 
+// rootsFlag collects the directories granted by --allow-fs.
+type rootsFlag []string
+
+func (roots *rootsFlag) String() string {
+	return strings.Join(*roots, ",")
+}
+
+func (roots *rootsFlag) Set(value string) error {
+	*roots = append(*roots, value)
+	return nil
+}
+
+var allowFS rootsFlag
+
 func main() {
-	if len(os.Args) > 2 {
-		fmt.Println("Usage: lox [script]")
-	} else if len(os.Args) == 2 {
-		sourcePath, err := filepath.Abs(os.Args[1])
+	flag.Var(&allowFS, "allow-fs", "grant scripts access to files under `dir` (repeatable).")
+	flag.Usage = func() {
+		fmt.Fprintln(flag.CommandLine.Output(), "Usage: lox [--allow-fs=dir] [script [args...]]")
+		flag.PrintDefaults()
+	}
+	flag.Parse()
+	args := flag.Args()
+
+	if len(args) >= 1 {
+		sourcePath, err := filepath.Abs(args[0])
 		if err != nil {
 			fmt.Println("Unable to find path ")
 			os.Exit(-1)
 		}
-		RunFile(sourcePath)
+		// the rest of the arguments are passed to the script.
+		RunFile(sourcePath, args[1:])
 	} else {
 		RunPrompt()
 	}
 }
 
+// newInterpreter returns an interpreter configured by the command line flags.
+func newInterpreter(repl bool) *lox.Interpreter {
+	interpreter := lox.NewInterpreter(repl)
+
+	if err := interpreter.AllowFS(allowFS...); err != nil {
+		fmt.Printf("Unable to allow file system access: %v\n", err.Error())
+		os.Exit(1)
+	}
+	return interpreter
+}
+
 func run(interpreter *lox.Interpreter, source string) (hadError, hadRuntimeError bool) {
 	scanner := lox.NewScanner(source)
 	tokens, hadError := scanner.ScanTokens()
@@ -51,15 +85,16 @@ func run(interpreter *lox.Interpreter, source string) (hadError, hadRuntimeError
 	return
 }
 
-// RunFile runs a lox script file.
-func RunFile(path string) {
+// RunFile runs a lox script file, `args` are exposed to the script as os.args.
+func RunFile(path string, args []string) {
 	var (
 		dat    []byte
 		source string
 		err    error
 	)
 
-	interpreter := lox.NewInterpreter(false)
+	interpreter := newInterpreter(false)
+	interpreter.SetArgs(args)
 
 	if dat, err = ioutil.ReadFile(path); err != nil {
 		fmt.Printf("Unable to read from file: %v.\n %v", path, err.Error())
@@ -68,6 +103,9 @@ func RunFile(path string) {
 	source = string(dat)
 	hadError, hadRuntimeError := run(interpreter, source)
 
+	if code, exited := interpreter.Exited(); exited {
+		os.Exit(code)
+	}
 	if hadError {
 		os.Exit(65)
 	}
@@ -85,7 +123,7 @@ func RunPrompt() {
 		fmt.Println(err.Error())
 		os.Exit(80)
 	}
-	interpreter := lox.NewInterpreter(true)
+	interpreter := newInterpreter(true)
 
 	for {
 		fmt.Print("> ")
@@ -103,5 +141,8 @@ func RunPrompt() {
 			os.Exit(80)
 		}
 		run(interpreter, string(line))
+		if code, exited := interpreter.Exited(); exited {
+			os.Exit(code)
+		}
 	}
 }
@@ -1,7 +1,9 @@
 package lox
 
 import (
-	"strconv"
+	"fmt"
+	"sort"
+	"strings"
 )
 
 // LoxArray is the runtime object for lox array.
@@ -16,56 +18,139 @@ func newArraryInsType(o *LoxInstance) *_arrayInsType {
 	return &_arrayInsType{o}
 }
 
-// we gonna reimplement the general method.
+// String formats the array like print does, without calling toString().
 func (o *_arrayInsType) String() string {
-	stringified := "["
-	list, _ := o.props["list"].([]interface{})
-	for _, item := range list {
-		var itemStr string
-		switch val := item.(type) {
-		case string:
-			itemStr = val
-		case int:
-			itemStr = strconv.Itoa(val)
-		case float64:
-			itemStr = strconv.FormatFloat(val, 'f', -1, 64)
-		case bool:
-			if val == true {
-				itemStr = "true"
-			} else {
-				itemStr = "false"
+	return formatValue(o)
+}
+
+// newArray creates a lox array holding `list`.
+func newArray(interp *Interpreter, list []interface{}) *_arrayInsType {
+	array, _ := LoxArray.Call(interp, list...).(*_arrayInsType)
+	return array
+}
+
+func arrayList(i *LoxInstance) []interface{} {
+	list, _ := i.native.([]interface{})
+	return list
+}
+
+// arrayArg returns the list of the array args[index] for native function `name`.
+func arrayArg(name string, args []interface{}, index int) []interface{} {
+	array, ok := args[index].(*_arrayInsType)
+	if !ok {
+		panic(NewRuntimeError(nil, fmt.Sprintf("%v() expects argument %v to be an array.", name, index+1)))
+	}
+	return arrayList(array.LoxInstance)
+}
+
+// indexArg returns args[index] as an index into `list`, counting from the end
+// if it is negative. `inclusive` allows the index right after the last element.
+func indexArg(name string, args []interface{}, index int, list []interface{}, inclusive bool) int {
+	pos := intArg(name, args, index)
+	if pos < 0 {
+		pos += len(list)
+	}
+
+	upper := len(list)
+	if inclusive {
+		upper++
+	}
+	if pos < 0 || pos >= upper {
+		panic(NewRuntimeError(nil, fmt.Sprintf("%v() index %v out of range.", name, args[index])))
+	}
+	return pos
+}
+
+// compareValues is the default ordering of Array.sort.
+// Numbers & strings are supported, but they can't be mixed.
+func compareValues(a, b interface{}) int {
+	if x, ok := a.(string); ok {
+		if y, ok := b.(string); ok {
+			return strings.Compare(x, y)
+		}
+	} else if x, ok := numberValue(a); ok {
+		if y, ok := numberValue(b); ok {
+			switch {
+			case x < y:
+				return -1
+			case x > y:
+				return 1
+			default:
+				return 0
 			}
 		}
-		stringified += itemStr + ", "
 	}
-	if len(list) > 0 {
-		stringified = stringified[:len(stringified)-2]
+	panic(NewRuntimeError(nil, "sort() can only compare numbers or strings without a comparator."))
+}
+
+func numberValue(value interface{}) (float64, bool) {
+	switch val := value.(type) {
+	case int:
+		return float64(val), true
+	case float64:
+		return val, true
+	default:
+		return 0, false
+	}
+}
+
+// iterate calls `fn` with each element, its index & the array, until `stop` returns true.
+// It returns the index where it stopped, or -1.
+func iterate(interp *Interpreter, name string, i *LoxInstance, args []interface{}, stop func(result interface{}) bool) int {
+	fn := callableArg(name, args, 0)
+	array := newArraryInsType(i)
+
+	// the callback might modify the array, so we iterate over a snapshot.
+	for index, elem := range append([]interface{}{}, arrayList(i)...) {
+		if stop(interp.invoke(fn, elem, index, array)) {
+			return index
+		}
 	}
-	//stringified = stringified[:len(stringified)-1]
-	stringified += "]"
-	return stringified
+	return -1
 }
 
 // init Array class. This function will be called when an Interpreter is instantiated.
 func initArray() {
 	// Array static methods
 	var statics = map[string]Callable{
-		"isArray": NewBuiltinFunc(1, func(i *LoxInstance, args ...interface{}) interface{} {
-			obj, ok := args[0].(*LoxInstance)
-			if ok != true {
-				return false
+		"isArray": NewBuiltinFunc(1, func(interp *Interpreter, i *LoxInstance, args ...interface{}) interface{} {
+			_, ok := args[0].(*_arrayInsType)
+			return ok
+		}),
+		// range(end), range(start, end) or range(start, end, step).
+		"range": NewBuiltinFunc(-1, func(interp *Interpreter, i *LoxInstance, args ...interface{}) interface{} {
+			checkArgs("range", args, 1, 3)
+			start, end, step := 0, intArg("range", args, 0), 1
+			if len(args) > 1 {
+				start, end = end, intArg("range", args, 1)
 			}
-			if obj.class.Name != "Array" {
-				return false
+			if len(args) > 2 {
+				step = intArg("range", args, 2)
+			}
+			if step == 0 {
+				panic(NewRuntimeError(nil, "range() step must not be zero."))
+			}
+
+			count := 0
+			if step > 0 && end > start {
+				count = (end - start + step - 1) / step
+			} else if step < 0 && end < start {
+				count = (start - end - step - 1) / -step
 			}
-			return true
+			interp.alloc(sizeSlot * count)
+
+			list := make([]interface{}, count)
+			for index := range list {
+				list[index] = start + index*step
+			}
+			return newArray(interp, list)
 		}),
 	}
 
 	// instance methods
 	var methods = map[string]Callable{
 		// We mark the arity to be -1, means we accept inifinite args.
-		"init": NewBuiltinFunc(-1, func(i *LoxInstance, args ...interface{}) interface{} {
+		"init": NewBuiltinFunc(-1, func(interp *Interpreter, i *LoxInstance, args ...interface{}) interface{} {
 			argsLen := len(args)
 			list := []interface{}{}
 			if argsLen != 0 {
@@ -73,28 +158,216 @@ func initArray() {
 					list = append(list, obj)
 				}
 			}
-			i.props["list"] = list
+			interp.alloc(sizeSlot * len(list))
+			i.native = list
 			return newArraryInsType(i)
 		}),
-		"append": NewBuiltinFunc(-1, func(i *LoxInstance, args ...interface{}) interface{} {
-			list, _ := i.props["list"].([]interface{})
+		"append": NewBuiltinFunc(-1, func(interp *Interpreter, i *LoxInstance, args ...interface{}) interface{} {
+			i.checkFrozen(nil)
+			list, _ := i.native.([]interface{})
+			interp.alloc(sizeSlot * len(args))
 			list = append(list, args...)
-			i.props["list"] = list
+			i.native = list
 			return len(list)
 		}),
-		"pop": NewBuiltinFunc(0, func(i *LoxInstance, args ...interface{}) interface{} {
-			list, _ := i.props["list"].([]interface{})
+		"pop": NewBuiltinFunc(0, func(interp *Interpreter, i *LoxInstance, args ...interface{}) interface{} {
+			i.checkFrozen(nil)
+			list, _ := i.native.([]interface{})
+			if len(list) == 0 {
+				panic(NewRuntimeError(nil, "pop() from an empty array."))
+			}
 			returned := list[len(list)-1]
-			list = list[:len(list)]
-			i.props["list"] = list
+			list = list[:len(list)-1]
+			i.native = list
 			return returned
 		}),
+		// insert(index, value) inserts `value` before `index`.
+		"insert": NewBuiltinFunc(2, func(interp *Interpreter, i *LoxInstance, args ...interface{}) interface{} {
+			i.checkFrozen(nil)
+			list := arrayList(i)
+			index := indexArg("insert", args, 0, list, true)
+			interp.alloc(sizeSlot)
+
+			list = append(list, nil)
+			copy(list[index+1:], list[index:])
+			list[index] = args[1]
+			i.native = list
+			return len(list)
+		}),
+		// remove(index) removes the element at `index` & returns it.
+		"remove": NewBuiltinFunc(1, func(interp *Interpreter, i *LoxInstance, args ...interface{}) interface{} {
+			i.checkFrozen(nil)
+			list := arrayList(i)
+			index := indexArg("remove", args, 0, list, false)
+
+			removed := list[index]
+			i.native = append(list[:index], list[index+1:]...)
+			return removed
+		}),
+		"indexOf": NewBuiltinFunc(1, func(interp *Interpreter, i *LoxInstance, args ...interface{}) interface{} {
+			for index, elem := range arrayList(i) {
+				if equal(elem, args[0]) {
+					return index
+				}
+			}
+			return -1
+		}),
+		"includes": NewBuiltinFunc(1, func(interp *Interpreter, i *LoxInstance, args ...interface{}) interface{} {
+			for _, elem := range arrayList(i) {
+				if equal(elem, args[0]) {
+					return true
+				}
+			}
+			return false
+		}),
+		// slice(start, end?) returns a new array, negative indices count from the end.
+		"slice": NewBuiltinFunc(-1, func(interp *Interpreter, i *LoxInstance, args ...interface{}) interface{} {
+			checkArgs("slice", args, 1, 2)
+			list := arrayList(i)
+			start := clampIndex(intArg("slice", args, 0), len(list))
+			end := len(list)
+			if len(args) == 2 {
+				end = clampIndex(intArg("slice", args, 1), len(list))
+			}
+			if start >= end {
+				return newArray(interp, nil)
+			}
+			return newArray(interp, list[start:end])
+		}),
+		"concat": NewBuiltinFunc(-1, func(interp *Interpreter, i *LoxInstance, args ...interface{}) interface{} {
+			list := append([]interface{}{}, arrayList(i)...)
+			for index := range args {
+				list = append(list, arrayArg("concat", args, index)...)
+			}
+			return newArray(interp, list)
+		}),
+		// reverse() reverses the array in place & returns it.
+		"reverse": NewBuiltinFunc(0, func(interp *Interpreter, i *LoxInstance, args ...interface{}) interface{} {
+			i.checkFrozen(nil)
+			list := arrayList(i)
+			for left, right := 0, len(list)-1; left < right; left, right = left+1, right-1 {
+				list[left], list[right] = list[right], list[left]
+			}
+			return newArraryInsType(i)
+		}),
+		"join": NewBuiltinFunc(-1, func(interp *Interpreter, i *LoxInstance, args ...interface{}) interface{} {
+			checkArgs("join", args, 0, 1)
+			separator := ","
+			if len(args) == 1 {
+				separator = stringArg("join", args, 0)
+			}
+
+			list := arrayList(i)
+			parts := make([]string, len(list))
+			for index, elem := range list {
+				if elem != nil {
+					parts[index] = interp.stringify(elem)
+				}
+			}
+			joined := strings.Join(parts, separator)
+			interp.alloc(sizeString + len(joined))
+			return joined
+		}),
+		// sort(comparator?) sorts the array in place & returns it. The sort is stable.
+		// `comparator(a, b)` returns a negative number if `a` goes before `b`.
+		"sort": NewBuiltinFunc(-1, func(interp *Interpreter, i *LoxInstance, args ...interface{}) interface{} {
+			i.checkFrozen(nil)
+			checkArgs("sort", args, 0, 1)
+			compare := compareValues
+			if len(args) == 1 {
+				comparator := callableArg("sort", args, 0)
+				compare = func(a, b interface{}) int {
+					result, ok := numberValue(interp.invoke(comparator, a, b))
+					if !ok {
+						panic(NewRuntimeError(nil, "sort() comparator must return a number."))
+					}
+					if result < 0 {
+						return -1
+					} else if result > 0 {
+						return 1
+					}
+					return 0
+				}
+			}
+
+			list := arrayList(i)
+			sort.SliceStable(list, func(x, y int) bool {
+				return compare(list[x], list[y]) < 0
+			})
+			return newArraryInsType(i)
+		}),
+
+		// higher-order methods. Callbacks are called with (element, index, array).
+		"forEach": NewBuiltinFunc(1, func(interp *Interpreter, i *LoxInstance, args ...interface{}) interface{} {
+			iterate(interp, "forEach", i, args, func(result interface{}) bool { return false })
+			return nil
+		}),
+		"map": NewBuiltinFunc(1, func(interp *Interpreter, i *LoxInstance, args ...interface{}) interface{} {
+			list := make([]interface{}, 0, len(arrayList(i)))
+			iterate(interp, "map", i, args, func(result interface{}) bool {
+				list = append(list, result)
+				return false
+			})
+			return newArray(interp, list)
+		}),
+		"filter": NewBuiltinFunc(1, func(interp *Interpreter, i *LoxInstance, args ...interface{}) interface{} {
+			snapshot := append([]interface{}{}, arrayList(i)...)
+			list := make([]interface{}, 0)
+			index := 0
+			iterate(interp, "filter", i, args, func(result interface{}) bool {
+				if truthy(result) {
+					list = append(list, snapshot[index])
+				}
+				index++
+				return false
+			})
+			return newArray(interp, list)
+		}),
+		// reduce(fn, initial?) calls fn(accumulator, element, index, array).
+		"reduce": NewBuiltinFunc(-1, func(interp *Interpreter, i *LoxInstance, args ...interface{}) interface{} {
+			checkArgs("reduce", args, 1, 2)
+			fn := callableArg("reduce", args, 0)
+			list := append([]interface{}{}, arrayList(i)...)
+			array := newArraryInsType(i)
+
+			var accumulator interface{}
+			start := 0
+			if len(args) == 2 {
+				accumulator = args[1]
+			} else if len(list) == 0 {
+				panic(NewRuntimeError(nil, "reduce() of an empty array with no initial value."))
+			} else {
+				accumulator = list[0]
+				start = 1
+			}
+
+			for index := start; index < len(list); index++ {
+				accumulator = interp.invoke(fn, accumulator, list[index], index, array)
+			}
+			return accumulator
+		}),
+		"find": NewBuiltinFunc(1, func(interp *Interpreter, i *LoxInstance, args ...interface{}) interface{} {
+			snapshot := append([]interface{}{}, arrayList(i)...)
+			if index := iterate(interp, "find", i, args, truthy); index >= 0 {
+				return snapshot[index]
+			}
+			return nil
+		}),
+		"findIndex": NewBuiltinFunc(1, func(interp *Interpreter, i *LoxInstance, args ...interface{}) interface{} {
+			return iterate(interp, "findIndex", i, args, truthy)
+		}),
+		"some": NewBuiltinFunc(1, func(interp *Interpreter, i *LoxInstance, args ...interface{}) interface{} {
+			return iterate(interp, "some", i, args, truthy) >= 0
+		}),
+		"every": NewBuiltinFunc(1, func(interp *Interpreter, i *LoxInstance, args ...interface{}) interface{} {
+			return iterate(interp, "every", i, args, func(result interface{}) bool { return !truthy(result) }) < 0
+		}),
 	}
 
 	// instance getters
 	var getters = map[string]Callable{
-		"length": NewBuiltinFunc(0, func(i *LoxInstance, args ...interface{}) interface{} {
-			list, _ := i.props["list"].([]interface{})
+		"length": NewBuiltinFunc(0, func(interp *Interpreter, i *LoxInstance, args ...interface{}) interface{} {
+			list, _ := i.native.([]interface{})
 			return len(list)
 		}),
 	}
@@ -62,6 +62,54 @@ func (p *AstPrinter) VisitClassStmt(stmt *Class) interface{} {
 	ast += ")\n"
 	return ast
 }
+func (p *AstPrinter) VisitEnumStmt(stmt *Enum) interface{} {
+	ast := getIndents(p.indents) + "(enum " + stmt.Name.Lexeme
+	for _, member := range stmt.Members {
+		ast += " " + member.Lexeme
+	}
+	ast += "\n"
+
+	p.indents++
+	defer func() {
+		p.indents--
+	}()
+
+	for _, method := range stmt.Methods {
+		val, _ := method.Accept(p).(string)
+		ast += val
+	}
+	ast += ")\n"
+	return ast
+}
+
+func (p *AstPrinter) VisitInterfaceStmt(stmt *Interface) interface{} {
+	ast := getIndents(p.indents) + "(interface " + stmt.Name.Lexeme
+	for _, method := range stmt.Methods {
+		ast += " " + method.Name.Lexeme + "/" + fmt.Sprint(len(method.Params))
+	}
+	for _, getter := range stmt.Getters {
+		ast += " get " + getter.Name.Lexeme
+	}
+	ast += ")\n"
+	return ast
+}
+
+func (p *AstPrinter) VisitTraitStmt(stmt *Trait) interface{} {
+	ast := getIndents(p.indents) + "(trait " + stmt.Name.Lexeme + "\n"
+
+	p.indents++
+	defer func() {
+		p.indents--
+	}()
+
+	for _, method := range stmt.Methods {
+		val, _ := method.Accept(p).(string)
+		ast += val
+	}
+	ast += ")\n"
+	return ast
+}
+
 func (p *AstPrinter) VisitControlStmt(stmt *Control) interface{} {
 	ast := getIndents(p.indents) + "(" + stmt.Keyword.Lexeme
 	if stmt.Keyword.Lexeme == "break" {
@@ -125,6 +173,10 @@ func (p *AstPrinter) VisitVarStmt(stmt *Var) interface{} {
 	return getIndents(p.indents) + p.parenthesize("var", stmt.Name, "=", stmt.Initializer) + "\n"
 }
 
+func (p *AstPrinter) VisitVarDestructureStmt(stmt *VarDestructure) interface{} {
+	return getIndents(p.indents) + p.parenthesize(stmt.Keyword.Lexeme, p.pattern(stmt.Pattern), "=", stmt.Initializer) + "\n"
+}
+
 func (p *AstPrinter) VisitVarListStmt(stmt *VarList) interface{} {
 	ast := ""
 
@@ -153,10 +205,24 @@ func (p *AstPrinter) VisitBinaryExpr(expr *Binary) interface{} {
 }
 
 func (p *AstPrinter) VisitCallExpr(expr *Call) interface{} {
+	if expr.Optional {
+		return p.parenthesize("?call", expr.Callee, expr.Arguments)
+	}
 	return p.parenthesize("call", expr.Callee, expr.Arguments)
 }
 
+func (p *AstPrinter) VisitChainExpr(expr *Chain) interface{} {
+	return p.parenthesize("chain", expr.Expression)
+}
+
+func (p *AstPrinter) VisitConditionalExpr(expr *Conditional) interface{} {
+	return p.parenthesize("?:", expr.Condition, expr.ThenBranch, expr.ElseBranch)
+}
+
 func (p *AstPrinter) VisitGetExpr(expr *Get) interface{} {
+	if expr.Optional {
+		return p.parenthesize("?get", expr.Object, expr.Name)
+	}
 	return p.parenthesize("get", expr.Object, expr.Name)
 }
 
@@ -208,11 +274,86 @@ func (p *AstPrinter) VisitLogicalExpr(expr *Logical) interface{} {
 	return p.parenthesize(expr.Operator.Lexeme, expr.Left, expr.Right)
 }
 
+func (p *AstPrinter) VisitDestructureExpr(expr *Destructure) interface{} {
+	return p.parenthesize("=", p.pattern(expr.Pattern), expr.Value)
+}
+
+func (p *AstPrinter) VisitMatchExpr(expr *Match) interface{} {
+	ast := "(match " + p.parenthesize("subject", expr.Subject)
+	for _, c := range expr.Cases {
+		ast += " (case"
+		for _, pattern := range c.Patterns {
+			ast += " " + p.pattern(pattern)
+		}
+		if c.Guard != nil {
+			ast += " " + p.parenthesize("if", c.Guard)
+		}
+		if c.Body != nil {
+			ast += " " + p.parenthesize("->", c.Body)
+		} else {
+			ast += " " + p.parenthesize("->", c.Value)
+		}
+		ast += ")"
+	}
+	return ast + ")"
+}
+
+func (p *AstPrinter) pattern(pattern Pattern) string {
+	switch pattern := pattern.(type) {
+	case *ValuePattern:
+		val, _ := pattern.Value.Accept(p).(string)
+		return val
+	case *RangePattern:
+		return p.parenthesize("..", pattern.Low, pattern.High)
+	case *BindingPattern:
+		return pattern.Name.Lexeme
+	case *ClassPattern:
+		ast := "(" + pattern.Class.Name.Lexeme
+		for _, field := range pattern.Fields {
+			ast += " " + p.pattern(field)
+		}
+		return ast + ")"
+	case *ObjectPattern:
+		ast := "{"
+		for index, key := range pattern.Keys {
+			if index != 0 {
+				ast += " "
+			}
+			ast += key.Lexeme + ": " + p.pattern(pattern.Values[index])
+		}
+		return ast + "}"
+	case *DefaultPattern:
+		return p.parenthesize("default", p.pattern(pattern.Pattern), pattern.Default)
+	case *VariablePattern:
+		return pattern.Variable.Name.Lexeme
+	case *ArrayPattern:
+		ast := "["
+		for index, element := range pattern.Elements {
+			if index != 0 {
+				ast += " "
+			}
+			ast += p.pattern(element)
+		}
+		if pattern.Rest != nil {
+			ast += " ..." + p.pattern(pattern.Rest)
+		}
+		return ast + "]"
+	}
+	return ""
+}
+
 func (p *AstPrinter) VisitSetExpr(expr *Set) interface{} {
 	return p.parenthesize("set", expr.Object, expr.Name, expr.Value)
 }
 
+func (p *AstPrinter) VisitSpreadExpr(expr *Spread) interface{} {
+	return p.parenthesize("...", expr.Expression)
+}
+
 func (p *AstPrinter) VisitSubscriptExpr(expr *Subscript) interface{} {
+	if expr.Optional {
+		return p.parenthesize("?subscript", expr.Object, expr.Key)
+	}
 	return p.parenthesize("subscript", expr.Object, expr.Key)
 }
 
@@ -220,6 +361,10 @@ func (p *AstPrinter) VisitSuperExpr(expr *Super) interface{} {
 	return p.parenthesize("super", expr.Method)
 }
 
+func (p *AstPrinter) VisitSuperSetExpr(expr *SuperSet) interface{} {
+	return p.parenthesize("super-set", expr.Name, expr.Value)
+}
+
 func (p *AstPrinter) VisitThisExpr(expr *This) interface{} {
 	return "this"
 }
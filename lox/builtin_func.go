@@ -1,14 +1,17 @@
 package lox
 
+import "fmt"
+
 // BuiltInFunc is the runtime representation of builtin functions
 type BuiltInFunc struct {
+	name     string // the key it is defined by, in the globals or in a class.
 	arity    int
-	call     func(*LoxInstance, ...interface{}) interface{} // internal go function
+	call     func(*Interpreter, *LoxInstance, ...interface{}) interface{} // internal go function
 	instance *LoxInstance
 }
 
 // NewBuiltinFunc returns a new built-in functions.
-func NewBuiltinFunc(arity int, call func(*LoxInstance, ...interface{}) interface{}) *BuiltInFunc {
+func NewBuiltinFunc(arity int, call func(*Interpreter, *LoxInstance, ...interface{}) interface{}) *BuiltInFunc {
 	return &BuiltInFunc{arity: arity, call: call, instance: nil}
 }
 
@@ -19,19 +22,89 @@ func (bf *BuiltInFunc) Arity() int {
 
 // Call implements the Callable interface.
 func (bf *BuiltInFunc) Call(interpreter *Interpreter, args ...interface{}) interface{} {
-	// we actually omit the `interpreter` because we already known how
-	// to convert it into a go function.
-	return bf.call(bf.instance, args...)
+	// the interpreter is passed along for memory accounting.
+	return bf.call(interpreter, bf.instance, args...)
 }
 
 // Bind is called when interpreting `Get` expression.
-// It is rather
+// It returns a copy, since the same native method is shared by all instances.
 func (bf *BuiltInFunc) Bind(instance *LoxInstance) Callable {
-	bf.instance = instance
-	// return itself.
-	return bf
+	return &BuiltInFunc{name: bf.name, arity: bf.arity, call: bf.call, instance: instance}
 }
 
 func (bf *BuiltInFunc) String() string {
 	return "<native function>"
 }
+
+// numberArg returns args[index] as a float64 for native function `name`.
+// The RuntimeError it panics with is located at the call site by the interpreter.
+func numberArg(name string, args []interface{}, index int) float64 {
+	switch val := args[index].(type) {
+	case int:
+		return float64(val)
+	case float64:
+		return val
+	default:
+		panic(NewRuntimeError(nil, fmt.Sprintf("%v() expects argument %v to be a number.", name, index+1)))
+	}
+}
+
+// intArg returns args[index] as an int for native function `name`.
+func intArg(name string, args []interface{}, index int) int {
+	val, ok := args[index].(int)
+	if !ok {
+		panic(NewRuntimeError(nil, fmt.Sprintf("%v() expects argument %v to be an integer.", name, index+1)))
+	}
+	return val
+}
+
+// stringArg returns args[index] as a string for native function `name`.
+func stringArg(name string, args []interface{}, index int) string {
+	val, ok := args[index].(string)
+	if !ok {
+		panic(NewRuntimeError(nil, fmt.Sprintf("%v() expects argument %v to be a string.", name, index+1)))
+	}
+	return val
+}
+
+// callableArg returns args[index] as a Callable for native function `name`.
+func callableArg(name string, args []interface{}, index int) Callable {
+	val, ok := args[index].(Callable)
+	if !ok {
+		panic(NewRuntimeError(nil, fmt.Sprintf("%v() expects argument %v to be callable.", name, index+1)))
+	}
+	return val
+}
+
+// invoke calls `callee` from native code, e.g. the callback of Array.map.
+// Trailing args are dropped if `callee` takes less of them, so a callback is
+// free to ignore the index & the array it is given.
+func (i *Interpreter) invoke(callee Callable, args ...interface{}) interface{} {
+	min, max := arityRange(callee)
+	if min > len(args) {
+		panic(NewRuntimeError(nil, fmt.Sprintf("expect a callback taking at most %v arguments, but it takes %v.", len(args), arityString(min, max))))
+	}
+	if max != -1 && max < len(args) {
+		args = args[:max]
+	}
+	return callee.Call(i, args...)
+}
+
+// checkArgs checks the number of args passed to a variadic native function `name`.
+// A negative `max` means there is no upper bound.
+func checkArgs(name string, args []interface{}, min, max int) {
+	if len(args) < min || (max >= 0 && len(args) > max) {
+		panic(NewRuntimeError(nil, fmt.Sprintf("%v() expects %v arguments, but got %v.", name, arityString(min, max), len(args))))
+	}
+}
+
+// arityString describes the number of args a function takes, e.g. "1 to 3".
+// A negative `max` means there is no upper bound.
+func arityString(min, max int) string {
+	if min == max {
+		return fmt.Sprint(min)
+	} else if max < 0 {
+		return fmt.Sprintf("at least %v", min)
+	}
+	return fmt.Sprintf("%v to %v", min, max)
+}
@@ -1,13 +1,29 @@
 package lox
 
 // LoxClass is a runtime object for a lox class.
+//
+// Static members are inherited: reading `Sub.x` finds the field, static method or
+// static getter `x` of the nearest class declaring one, and writing it calls the
+// static setter or writes the field of the nearest class declaring one, i.e. a
+// subclass shares the fields of its superclasses unless it declares its own.
+// Writing a field no class declares creates it on the class written to. Fields of
+// builtin classes are read-only.
 type LoxClass struct {
-	Name    string
-	Super   *LoxClass
-	Statics map[string]Callable // static props or functions.
-	Methods map[string]Callable // class methods.
-	Getters map[string]Callable // getters are functions in essence.
-	Setters map[string]Callable // setters are functions in essence.
+	Name          string
+	Super         *LoxClass
+	Statics       map[string]Callable    // static props or functions.
+	Methods       map[string]Callable    // class methods.
+	Getters       map[string]Callable    // getters are functions in essence.
+	Setters       map[string]Callable    // setters are functions in essence.
+	StaticGetters map[string]Callable    // getters on the class itself.
+	StaticSetters map[string]Callable    // setters on the class itself.
+	Fields        map[string]interface{} // static values, e.g. Math.PI.
+	Traits        []*LoxTrait            // traits composed by the class, in order.
+	Interfaces    []*LoxInterface        // interfaces the class declares to implement.
+	declared      bool                   // declared by a script, as opposed to builtin classes.
+	traits        *LoxClass              // the members of Traits, looked up before the ones of Super.
+	mixin         bool                   // holds the members of the traits composed by a class.
+	enum          []enumMember           // the members of an enum, in order.
 }
 
 // NewLoxClass returns a runtime object for a class
@@ -16,6 +32,9 @@ func NewLoxClass(name string,
 	statics,
 	methods, getters, setters map[string]Callable) *LoxClass {
 
+	for _, table := range []map[string]Callable{statics, methods, getters, setters} {
+		nameBuiltins(table)
+	}
 	return &LoxClass{
 		Name:    name,
 		Super:   super,
@@ -23,12 +42,23 @@ func NewLoxClass(name string,
 		Methods: methods,
 		Getters: getters,
 		Setters: setters,
+		Fields:  map[string]interface{}{},
+	}
+}
+
+// findInit returns the initializer of the class, which might be inherited.
+func (c *LoxClass) findInit() Callable {
+	for class := c; class != nil; class = class.Super {
+		if initializer, ok := class.Methods["init"]; ok {
+			return initializer
+		}
 	}
+	return nil
 }
 
 // Arity returns the number of args the initializer takes.
 func (c *LoxClass) Arity() int {
-	if initializer, ok := c.Methods["init"]; ok {
+	if initializer := c.findInit(); initializer != nil {
 		return initializer.Arity()
 	}
 	return 0
@@ -40,10 +70,14 @@ func (c *LoxClass) Bind(instance *LoxInstance) Callable {
 
 // Call returns a LoxInstance. It's a factory.
 func (c *LoxClass) Call(i *Interpreter, args ...interface{}) interface{} {
+	if c.enum != nil {
+		panic(NewRuntimeError(nil, "cannot create members of enum "+c.Name+"."))
+	}
+
+	i.alloc(sizeInstance)
 	instance := NewLoxInstance(c)
 
-	if init, ok := c.Methods["init"]; ok {
-		initializer, _ := init.(Callable)
+	if initializer := c.findInit(); initializer != nil {
 		// NOTE: this is another hack.
 		if ins := initializer.Bind(instance).Call(i, args...); ins != nil {
 			return ins
@@ -53,14 +87,74 @@ func (c *LoxClass) Call(i *Interpreter, args ...interface{}) interface{} {
 	return instance
 }
 
-// FindStatic returns the requested static method of the class.
+// FindStatic returns the requested static method of the class, which might be inherited.
 func (c *LoxClass) FindStatic(name string) Callable {
 	if val, ok := c.Statics[name]; ok {
 		return val
 	}
+
+	if c.Super != nil {
+		return c.Super.FindStatic(name)
+	}
 	return nil
 }
 
+// Get returns a static property, i.e. a static field, a static method or the value of a static getter.
+// The members of a class shadow the ones of its superclasses.
+func (c *LoxClass) Get(interpreter *Interpreter, name *Token) interface{} {
+	for class := c; class != nil; class = class.Super {
+		if field, ok := class.Fields[name.Lexeme]; ok {
+			return field
+		}
+
+		if static, ok := class.Statics[name.Lexeme]; ok {
+			return static
+		}
+
+		if getter, ok := class.StaticGetters[name.Lexeme]; ok {
+			return getter.Call(interpreter, nil)
+		}
+	}
+
+	panic(NewRuntimeError(name, "undefined static property '"+name.Lexeme+"'."))
+}
+
+// Set sets a static field, or calls a static setter. The members of a class shadow the ones of its superclasses.
+func (c *LoxClass) Set(interpreter *Interpreter, name *Token, value interface{}) interface{} {
+	var owner *LoxClass
+	for class := c; class != nil && owner == nil; class = class.Super {
+		if setter, ok := class.StaticSetters[name.Lexeme]; ok {
+			return setter.Call(interpreter, value)
+		}
+
+		if _, ok := class.Fields[name.Lexeme]; ok {
+			owner = class
+		}
+	}
+
+	if !c.declared {
+		panic(NewRuntimeError(name, "cannot set a property of builtin class '"+c.Name+"'."))
+	}
+	if c.enum != nil {
+		panic(NewRuntimeError(name, "cannot set a property of enum '"+c.Name+"'."))
+	}
+
+	if owner == nil {
+		interpreter.alloc(sizeSlot + len(name.Lexeme))
+		owner = c
+	}
+	owner.Fields[name.Lexeme] = value
+	return value
+}
+
+// parent returns the class the methods, getters & setters of `c` are inherited from.
+func (c *LoxClass) parent() *LoxClass {
+	if c.traits != nil {
+		return c.traits
+	}
+	return c.Super
+}
+
 // TODO: fix Find...
 
 // FindMethod returns a binded method.
@@ -69,8 +163,8 @@ func (c *LoxClass) FindMethod(instance *LoxInstance, name string) Callable {
 		return fn
 	}
 
-	if c.Super != nil {
-		return c.Super.FindMethod(instance, name)
+	if parent := c.parent(); parent != nil {
+		return parent.FindMethod(instance, name)
 	}
 
 	return nil
@@ -82,8 +176,8 @@ func (c *LoxClass) FindGetter(instance *LoxInstance, name string) Callable {
 		return fn
 	}
 
-	if c.Super != nil {
-		return c.Super.FindGetter(instance, name)
+	if parent := c.parent(); parent != nil {
+		return parent.FindGetter(instance, name)
 	}
 
 	return nil
@@ -95,8 +189,8 @@ func (c *LoxClass) FindSetter(instance *LoxInstance, name string) Callable {
 		return fn
 	}
 
-	if c.Super != nil {
-		return c.Super.FindSetter(instance, name)
+	if parent := c.parent(); parent != nil {
+		return parent.FindSetter(instance, name)
 	}
 
 	return nil
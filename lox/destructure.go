@@ -0,0 +1,109 @@
+package lox
+
+// Destructuring binds the parts of a value to names with the patterns of match,
+// e.g. `var [a, b, ...rest] = array`, `const {x, y = 0} = point` or the parameter
+// `fun f([a, b]) {}`. Only names, arrays & objects patterns can be declared.
+// `[a, b] = [b, a]` assigns to existing variables, once the whole value matched.
+// A value not matching the pattern is an error, which assigns none of them.
+
+// declarable reports whether `pattern` only binds names, as declarations require.
+func declarable(pattern Pattern) bool {
+	switch pattern := pattern.(type) {
+	case *BindingPattern:
+		return true
+	case *DefaultPattern:
+		return declarable(pattern.Pattern)
+	case *ArrayPattern:
+		for _, element := range pattern.Elements {
+			if !declarable(element) {
+				return false
+			}
+		}
+		return true
+	case *ObjectPattern:
+		for _, value := range pattern.Values {
+			if !declarable(value) {
+				return false
+			}
+		}
+		return true
+	}
+	return false
+}
+
+// boundNames returns the names bound by a declarable pattern, `_` excluded.
+func boundNames(pattern Pattern) []*Token {
+	var names []*Token
+	switch pattern := pattern.(type) {
+	case *BindingPattern:
+		if !wildcard(pattern.Name) {
+			names = append(names, pattern.Name)
+		}
+	case *DefaultPattern:
+		names = boundNames(pattern.Pattern)
+	case *ArrayPattern:
+		for _, element := range pattern.Elements {
+			names = append(names, boundNames(element)...)
+		}
+		if pattern.Rest != nil {
+			names = append(names, boundNames(pattern.Rest)...)
+		}
+	case *ObjectPattern:
+		for _, value := range pattern.Values {
+			names = append(names, boundNames(value)...)
+		}
+	}
+	return names
+}
+
+// VisitVarDestructureStmt declares the names bound by a pattern.
+func (i *Interpreter) VisitVarDestructureStmt(stmt *VarDestructure) interface{} {
+	value := i.evaluate(stmt.Initializer)
+	if !i.matchPattern(stmt.Pattern, value) {
+		panic(NewRuntimeError(stmt.Keyword, "cannot destructure "+i.stringify(value)+"."))
+	}
+
+	if stmt.Constant {
+		for _, name := range boundNames(stmt.Pattern) {
+			i.environment.DefineConst(name.Lexeme, i.environment.values[name.Lexeme])
+		}
+	}
+	return nil
+}
+
+// assignment is an assignment to an existing variable, deferred until the whole pattern matched.
+type assignment struct {
+	variable *Variable
+	value    interface{}
+}
+
+// VisitDestructureExpr assigns the parts of a value to existing variables.
+func (i *Interpreter) VisitDestructureExpr(expr *Destructure) interface{} {
+	value := i.evaluate(expr.Value)
+
+	// defaults might destructure too.
+	prevAssignments := i.assignments
+	i.assignments = nil
+	defer func() { i.assignments = prevAssignments }()
+
+	if !i.matchPattern(expr.Pattern, value) {
+		panic(NewRuntimeError(expr.Operator, "cannot destructure "+i.stringify(value)+"."))
+	}
+
+	owners := make([]*Environment, len(i.assignments))
+	for index, a := range i.assignments {
+		owners[index] = i.variableEnv(a.variable).owner(a.variable.Name)
+	}
+	for index, a := range i.assignments {
+		owners[index].values[a.variable.Name.Lexeme] = a.value
+	}
+	return value
+}
+
+// variableEnv returns the environment `variable` is resolved to, or the global one.
+func (i *Interpreter) variableEnv(variable *Variable) *Environment {
+	if distance, ok := i.locals[variable]; ok {
+		return i.environment.ancestor(distance)
+	}
+	return i.global
+}
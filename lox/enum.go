@@ -0,0 +1,75 @@
+package lox
+
+// Enums declare a fixed set of named values, e.g. `enum Color { Red, Green, Blue }`.
+// An enum is a class whose only instances are its members, which are static fields
+// of the class, e.g. `Color.Red`. Members have `name` & `ordinal` getters, print as
+// `Color.Red` and are equal to themselves only. Members are frozen, see freeze.go.
+// Methods & getters may be declared after the members, separated by a `;`.
+// `Color.values()` returns the members in order, `Color.from(name)` the member
+// named `name`, or nil.
+
+// enumMember is a member of an enum.
+type enumMember struct {
+	name     string
+	instance *LoxInstance
+}
+
+// member returns the member of the enum `c` which is `instance`.
+func (c *LoxClass) member(instance *LoxInstance) (int, *enumMember) {
+	for ordinal := range c.enum {
+		if c.enum[ordinal].instance == instance {
+			return ordinal, &c.enum[ordinal]
+		}
+	}
+	panic(NewRuntimeError(nil, "not a member of enum "+c.Name+"."))
+}
+
+// class returns the class an enum is declared as.
+func (stmt *Enum) class() *Class {
+	empty := []*Function{}
+	class, _ := NewClass(stmt.Name, nil, nil, nil, empty, stmt.Methods, stmt.Getters, stmt.Setters, empty, empty, nil).(*Class)
+	return class
+}
+
+// VisitEnumStmt declares an enum as a class & creates its members.
+func (i *Interpreter) VisitEnumStmt(stmt *Enum) interface{} {
+	i.VisitClassStmt(stmt.class())
+	class, _ := i.environment.values[stmt.Name.Lexeme].(*LoxClass)
+
+	class.Getters["name"] = NewBuiltinFunc(0, func(interp *Interpreter, i *LoxInstance, args ...interface{}) interface{} {
+		_, member := i.class.member(i)
+		return member.name
+	})
+	class.Getters["ordinal"] = NewBuiltinFunc(0, func(interp *Interpreter, i *LoxInstance, args ...interface{}) interface{} {
+		ordinal, _ := i.class.member(i)
+		return ordinal
+	})
+	class.Statics["values"] = NewBuiltinFunc(0, func(interp *Interpreter, i *LoxInstance, args ...interface{}) interface{} {
+		values := make([]interface{}, len(class.enum))
+		for ordinal, member := range class.enum {
+			values[ordinal] = member.instance
+		}
+		return newArray(interp, values)
+	})
+	class.Statics["from"] = NewBuiltinFunc(1, func(interp *Interpreter, i *LoxInstance, args ...interface{}) interface{} {
+		name := stringArg("from", args, 0)
+		for _, member := range class.enum {
+			if member.name == name {
+				return member.instance
+			}
+		}
+		return nil
+	})
+	nameBuiltins(class.Getters)
+	nameBuiltins(class.Statics)
+
+	i.alloc(sizeFunction*4 + (sizeInstance+sizeSlot)*len(stmt.Members))
+	class.enum = make([]enumMember, len(stmt.Members))
+	for ordinal, name := range stmt.Members {
+		instance := NewLoxInstance(class)
+		instance.frozen = true
+		class.enum[ordinal] = enumMember{name.Lexeme, instance}
+		class.Fields[name.Lexeme] = instance
+	}
+	return nil
+}
@@ -4,6 +4,7 @@ package lox
 type Environment struct {
 	enclosing *Environment
 	values    map[string]interface{}
+	consts    map[string]bool // names which can't be assigned.
 }
 
 // NewEnvironment returns an environment on top of `enclosing`.
@@ -15,6 +16,17 @@ func NewEnvironment(enclosing *Environment) *Environment {
 // The caller need to make sure the name isn't defined twice.
 func (e *Environment) Define(name string, value interface{}) {
 	e.values[name] = value
+	// the REPL might redefine a constant as a variable.
+	delete(e.consts, name)
+}
+
+// DefineConst defines a constant, which can't be assigned afterward.
+func (e *Environment) DefineConst(name string, value interface{}) {
+	e.values[name] = value
+	if e.consts == nil {
+		e.consts = map[string]bool{}
+	}
+	e.consts[name] = true
 }
 
 // Get gets the value of `name` in the calling Environment.
@@ -48,14 +60,19 @@ func (e *Environment) ancestor(distance int) *Environment {
 // Assign assigns `value` to `name`.
 // This method panics if the name isn't defined yet.
 func (e *Environment) Assign(name *Token, value interface{}) {
-	if _, ok := e.values[name.Lexeme]; ok == true {
-		e.values[name.Lexeme] = value
-		return
-	}
+	e.owner(name).values[name.Lexeme] = value
+}
 
-	if e.enclosing != nil {
-		e.enclosing.Assign(name, value)
-		return
+// owner returns the environment defining `name`, which must be assignable.
+// This method panics if the name isn't defined yet or is a constant.
+func (e *Environment) owner(name *Token) *Environment {
+	for env := e; env != nil; env = env.enclosing {
+		if _, ok := env.values[name.Lexeme]; ok {
+			if env.consts[name.Lexeme] {
+				panic(NewRuntimeError(name, "cannot assign to constant '"+name.Lexeme+"'."))
+			}
+			return env
+		}
 	}
 	panic(NewRuntimeError(name, "undefined variable '"+name.Lexeme+"'."))
 }
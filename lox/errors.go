@@ -54,6 +54,11 @@ func NewRuntimeError(token *Token, message string) error {
 
 // error interface.
 func (err *RuntimeError) Error() string {
+	// errors raised by native code might not know where they occur.
+	if err.token == nil {
+		return fmt.Sprintf("Runtime Error: %v\n", err.message)
+	}
+
 	line := err.token.Line
 	where := err.token.Lexeme
 	message := err.message
@@ -64,3 +69,29 @@ func (err *RuntimeError) Error() string {
 
 	return fmt.Sprintf("[line %v] Runtime Error at %v: %v\n", line, where, message)
 }
+
+// OutOfMemoryError occurs when a script exceeds the memory limit of the interpreter.
+type OutOfMemoryError struct {
+	Used  int // approximate bytes used when the limit was hit.
+	Limit int
+}
+
+// NewOutOfMemoryError is a constructor.
+func NewOutOfMemoryError(used, limit int) error {
+	return &OutOfMemoryError{used, limit}
+}
+
+// error interface.
+func (err *OutOfMemoryError) Error() string {
+	return fmt.Sprintf("Runtime Error: out of memory (%v bytes used, limit is %v bytes).\n", err.Used, err.Limit)
+}
+
+// ExitError is thrown by os.exit to stop the script with an exit code.
+type ExitError struct {
+	Code int
+}
+
+// error interface.
+func (err *ExitError) Error() string {
+	return fmt.Sprintf("exit status %v", err.Code)
+}
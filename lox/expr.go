@@ -5,14 +5,20 @@ type ExprVisitor interface {
 	VisitArrayExpr(expr *Array) interface{}
 	VisitBinaryExpr(expr *Binary) interface{}
 	VisitCallExpr(expr *Call) interface{}
+	VisitChainExpr(expr *Chain) interface{}
+	VisitConditionalExpr(expr *Conditional) interface{}
+	VisitDestructureExpr(expr *Destructure) interface{}
 	VisitGetExpr(expr *Get) interface{}
 	VisitGroupingExpr(expr *Grouping) interface{}
 	VisitLambdaExpr(expr *Lambda) interface{}
 	VisitLiteralExpr(expr *Literal) interface{}
 	VisitLogicalExpr(expr *Logical) interface{}
+	VisitMatchExpr(expr *Match) interface{}
 	VisitSetExpr(expr *Set) interface{}
+	VisitSpreadExpr(expr *Spread) interface{}
 	VisitSubscriptExpr(expr *Subscript) interface{}
 	VisitSuperExpr(expr *Super) interface{}
+	VisitSuperSetExpr(expr *SuperSet) interface{}
 	VisitThisExpr(expr *This) interface{}
 	VisitUnaryExpr(expr *Unary) interface{}
 	VisitVariableExpr(expr *Variable) interface{}
@@ -63,22 +69,61 @@ type Call struct {
 	Callee    Expr
 	Paren     *Token
 	Arguments []Expr
+	Optional  bool
 }
 
-func NewCall(callee Expr, paren *Token, arguments []Expr) Expr {
-	return &Call{Callee: callee, Paren: paren, Arguments: arguments}
+func NewCall(callee Expr, paren *Token, arguments []Expr, optional bool) Expr {
+	return &Call{Callee: callee, Paren: paren, Arguments: arguments, Optional: optional}
 }
 func (expr *Call) Accept(v ExprVisitor) interface{} {
 	return v.VisitCallExpr(expr)
 }
 
+type Chain struct {
+	Expression Expr
+}
+
+func NewChain(expression Expr) Expr {
+	return &Chain{Expression: expression}
+}
+func (expr *Chain) Accept(v ExprVisitor) interface{} {
+	return v.VisitChainExpr(expr)
+}
+
+type Conditional struct {
+	Condition  Expr
+	ThenBranch Expr
+	ElseBranch Expr
+}
+
+func NewConditional(condition Expr, thenbranch Expr, elsebranch Expr) Expr {
+	return &Conditional{Condition: condition, ThenBranch: thenbranch, ElseBranch: elsebranch}
+}
+func (expr *Conditional) Accept(v ExprVisitor) interface{} {
+	return v.VisitConditionalExpr(expr)
+}
+
+type Destructure struct {
+	Pattern  Pattern
+	Operator *Token
+	Value    Expr
+}
+
+func NewDestructure(pattern Pattern, operator *Token, value Expr) Expr {
+	return &Destructure{Pattern: pattern, Operator: operator, Value: value}
+}
+func (expr *Destructure) Accept(v ExprVisitor) interface{} {
+	return v.VisitDestructureExpr(expr)
+}
+
 type Get struct {
-	Object Expr
-	Name   *Token
+	Object   Expr
+	Name     *Token
+	Optional bool
 }
 
-func NewGet(object Expr, name *Token) Expr {
-	return &Get{Object: object, Name: name}
+func NewGet(object Expr, name *Token, optional bool) Expr {
+	return &Get{Object: object, Name: name, Optional: optional}
 }
 func (expr *Get) Accept(v ExprVisitor) interface{} {
 	return v.VisitGetExpr(expr)
@@ -130,27 +175,55 @@ func (expr *Logical) Accept(v ExprVisitor) interface{} {
 	return v.VisitLogicalExpr(expr)
 }
 
+type Match struct {
+	Keyword   *Token
+	Subject   Expr
+	Cases     []*Case
+	Statement bool
+}
+
+func NewMatch(keyword *Token, subject Expr, cases []*Case, statement bool) Expr {
+	return &Match{Keyword: keyword, Subject: subject, Cases: cases, Statement: statement}
+}
+func (expr *Match) Accept(v ExprVisitor) interface{} {
+	return v.VisitMatchExpr(expr)
+}
+
 type Set struct {
-	Object Expr
-	Name   *Token
-	Value  Expr
+	Object   Expr
+	Name     *Token
+	Operator *Token
+	Value    Expr
 }
 
-func NewSet(object Expr, name *Token, value Expr) Expr {
-	return &Set{Object: object, Name: name, Value: value}
+func NewSet(object Expr, name *Token, operator *Token, value Expr) Expr {
+	return &Set{Object: object, Name: name, Operator: operator, Value: value}
 }
 func (expr *Set) Accept(v ExprVisitor) interface{} {
 	return v.VisitSetExpr(expr)
 }
 
+type Spread struct {
+	Ellipsis   *Token
+	Expression Expr
+}
+
+func NewSpread(ellipsis *Token, expression Expr) Expr {
+	return &Spread{Ellipsis: ellipsis, Expression: expression}
+}
+func (expr *Spread) Accept(v ExprVisitor) interface{} {
+	return v.VisitSpreadExpr(expr)
+}
+
 type Subscript struct {
-	Object  Expr
-	Key     Expr
-	Bracket *Token
+	Object   Expr
+	Key      Expr
+	Bracket  *Token
+	Optional bool
 }
 
-func NewSubscript(object Expr, key Expr, bracket *Token) Expr {
-	return &Subscript{Object: object, Key: key, Bracket: bracket}
+func NewSubscript(object Expr, key Expr, bracket *Token, optional bool) Expr {
+	return &Subscript{Object: object, Key: key, Bracket: bracket, Optional: optional}
 }
 func (expr *Subscript) Accept(v ExprVisitor) interface{} {
 	return v.VisitSubscriptExpr(expr)
@@ -168,6 +241,20 @@ func (expr *Super) Accept(v ExprVisitor) interface{} {
 	return v.VisitSuperExpr(expr)
 }
 
+type SuperSet struct {
+	Keyword  *Token
+	Name     *Token
+	Operator *Token
+	Value    Expr
+}
+
+func NewSuperSet(keyword *Token, name *Token, operator *Token, value Expr) Expr {
+	return &SuperSet{Keyword: keyword, Name: name, Operator: operator, Value: value}
+}
+func (expr *SuperSet) Accept(v ExprVisitor) interface{} {
+	return v.VisitSuperSetExpr(expr)
+}
+
 type This struct {
 	Keyword *Token
 }
@@ -0,0 +1,117 @@
+package lox
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// formatter converts lox values into strings. It is the one routine behind print,
+// the REPL, string concatenation & String(value).
+// Instances are printed by their `toString()` method if they have one, otherwise
+// as `Class { field: value, ... }` with sorted fields, members of enums as `Enum.Member`. An array or an instance
+// met again while it is being formatted is printed as `<cycle>`.
+type formatter struct {
+	interp   *Interpreter // nil if `toString()` can't be called.
+	visiting map[*LoxInstance]bool
+}
+
+// stringify formats `value`, strings are returned as is.
+func (i *Interpreter) stringify(value interface{}) string {
+	if i.formatting == nil {
+		i.formatting = map[*LoxInstance]bool{}
+	}
+	// the visiting set is shared with the nested calls made by `toString()`.
+	f := &formatter{interp: i, visiting: i.formatting}
+	return f.format(value, false)
+}
+
+// formatValue formats `value` without calling `toString()`.
+func formatValue(value interface{}) string {
+	f := &formatter{visiting: map[*LoxInstance]bool{}}
+	return f.format(value, false)
+}
+
+// enter marks `instance` as being formatted, it returns false if it already is.
+func (f *formatter) enter(instance *LoxInstance) bool {
+	if f.visiting[instance] {
+		return false
+	}
+	f.visiting[instance] = true
+	return true
+}
+
+// format formats `value`, strings nested in arrays & instances are quoted.
+func (f *formatter) format(value interface{}, nested bool) string {
+	switch val := value.(type) {
+	case nil:
+		return "nil"
+	case bool:
+		return strconv.FormatBool(val)
+	case int:
+		return strconv.Itoa(val)
+	case float64:
+		return fmt.Sprint(val)
+	case string:
+		if nested {
+			return "\"" + val + "\""
+		}
+		return val
+	case *_arrayInsType:
+		if !f.enter(val.LoxInstance) {
+			return "<cycle>"
+		}
+		defer delete(f.visiting, val.LoxInstance)
+
+		list := arrayList(val.LoxInstance)
+		elems := make([]string, len(list))
+		for index, elem := range list {
+			elems[index] = f.format(elem, true)
+		}
+		return "[" + strings.Join(elems, ", ") + "]"
+	case *LoxInstance:
+		if !f.enter(val) {
+			return "<cycle>"
+		}
+		defer delete(f.visiting, val)
+
+		if f.interp != nil {
+			if toString := val.class.FindMethod(val, "toString"); toString != nil {
+				s, ok := toString.Call(f.interp).(string)
+				if !ok {
+					panic(NewRuntimeError(nil, "toString() must return a string."))
+				}
+				return s
+			}
+		}
+		return f.formatFields(val)
+	case Callable, *LoxTrait, *LoxInterface:
+		return fmt.Sprint(val)
+	default:
+		return "<native>"
+	}
+}
+
+// formatFields formats the fields of `instance` sorted by their names.
+func (f *formatter) formatFields(instance *LoxInstance) string {
+	if instance.class.enum != nil {
+		_, member := instance.class.member(instance)
+		return instance.class.Name + "." + member.name
+	}
+
+	names := make([]string, 0, len(instance.props))
+	for name := range instance.props {
+		names = append(names, name)
+	}
+	if len(names) == 0 {
+		return instance.class.Name + " {}"
+	}
+	sort.Strings(names)
+
+	fields := make([]string, len(names))
+	for index, name := range names {
+		fields[index] = name + ": " + f.format(instance.props[name], true)
+	}
+	return instance.class.Name + " { " + strings.Join(fields, ", ") + " }"
+}
@@ -0,0 +1,38 @@
+package lox
+
+// `freeze(obj)` makes an instance or an array immutable: setting its fields,
+// private ones included, assigning its elements & calling the methods modifying
+// an array fail. Freezing is shallow, i.e. the values held by `obj` aren't frozen.
+
+// checkFrozen fails if the instance is frozen.
+func (o *LoxInstance) checkFrozen(token *Token) {
+	if o.frozen {
+		panic(NewRuntimeError(token, "cannot modify a frozen "+o.class.Name+"."))
+	}
+}
+
+// defineFreeze defines freeze(obj) & isFrozen(obj).
+func (i *Interpreter) defineFreeze() {
+	// freeze(obj) freezes an instance or an array & returns it.
+	i.global.Define("freeze", NewBuiltinFunc(1, func(interp *Interpreter, _ *LoxInstance, args ...interface{}) interface{} {
+		switch obj := args[0].(type) {
+		case *LoxInstance:
+			obj.frozen = true
+		case *_arrayInsType:
+			obj.frozen = true
+		default:
+			panic(NewRuntimeError(nil, "freeze() expects an instance or an array."))
+		}
+		return args[0]
+	}))
+
+	i.global.Define("isFrozen", NewBuiltinFunc(1, func(interp *Interpreter, _ *LoxInstance, args ...interface{}) interface{} {
+		switch obj := args[0].(type) {
+		case *LoxInstance:
+			return obj.frozen
+		case *_arrayInsType:
+			return obj.frozen
+		}
+		return false
+	}))
+}
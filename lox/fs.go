@@ -0,0 +1,208 @@
+package lox
+
+import (
+	"bufio"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+)
+
+// LoxFS is the runtime object for the builtin fs module.
+// Every function of the module fails unless the host grants access to some
+// directories with Interpreter.AllowFS, and only paths inside them are allowed.
+var LoxFS *LoxClass
+
+// LoxStat is the class of objects returned by fs.stat.
+var LoxStat *LoxClass
+
+// AllowFS grants scripts access to the file system under `roots`.
+func (i *Interpreter) AllowFS(roots ...string) error {
+	for _, root := range roots {
+		abs, err := filepath.Abs(root)
+		if err != nil {
+			return err
+		}
+		if abs, err = filepath.EvalSymlinks(abs); err != nil {
+			return err
+		}
+		i.fsRoots = append(i.fsRoots, abs)
+	}
+	return nil
+}
+
+// realPath resolves the symlinks of `path`, whose trailing components might not
+// exist yet. It fails if a component exists but can't be resolved, e.g. a dangling
+// symlink, since creating a file through it would escape the checks of fsPath.
+func realPath(path string) (string, bool) {
+	if real, err := filepath.EvalSymlinks(path); err == nil {
+		return real, true
+	}
+	if _, err := os.Lstat(path); !os.IsNotExist(err) {
+		return "", false
+	}
+
+	parent := filepath.Dir(path)
+	if parent == path {
+		return path, true
+	}
+	real, ok := realPath(parent)
+	return filepath.Join(real, filepath.Base(path)), ok
+}
+
+// fsPath returns args[index] as an absolute path with its symlinks resolved, which
+// must be inside one of the allowed roots.
+func fsPath(interp *Interpreter, name string, args []interface{}, index int) string {
+	if len(interp.fsRoots) == 0 {
+		panic(NewRuntimeError(nil, "fs."+name+"(): file system access is disabled."))
+	}
+
+	path := stringArg(name, args, index)
+	abs, err := filepath.Abs(path)
+	if err != nil {
+		panic(fsError(name, err))
+	}
+
+	if real, ok := realPath(abs); ok {
+		for _, root := range interp.fsRoots {
+			if rel, err := filepath.Rel(root, real); err == nil && rel != ".." && !strings.HasPrefix(rel, ".."+string(filepath.Separator)) {
+				return real
+			}
+		}
+	}
+	panic(NewRuntimeError(nil, fmt.Sprintf("fs.%v(): access to '%v' is denied.", name, path)))
+}
+
+func fsError(name string, err error) error {
+	return NewRuntimeError(nil, fmt.Sprintf("fs.%v(): %v.", name, err))
+}
+
+// chargeFile charges the size of the file at `path`, before it is read into memory.
+func chargeFile(interp *Interpreter, name, path string) {
+	info, err := os.Stat(path)
+	if err != nil {
+		panic(fsError(name, err))
+	}
+	interp.alloc(sizeString + int(info.Size()))
+}
+
+func writeFile(name string, flag int) *BuiltInFunc {
+	return NewBuiltinFunc(2, func(interp *Interpreter, i *LoxInstance, args ...interface{}) interface{} {
+		path := fsPath(interp, name, args, 0)
+		content := stringArg(name, args, 1)
+
+		file, err := os.OpenFile(path, flag, 0644)
+		if err == nil {
+			_, err = file.WriteString(content)
+			if closeErr := file.Close(); err == nil {
+				err = closeErr
+			}
+		}
+		if err != nil {
+			panic(fsError(name, err))
+		}
+		return nil
+	})
+}
+
+// init fs module. This function will be called when an Interpreter is instantiated.
+func initFS() {
+	var statics = map[string]Callable{
+		"readFile": NewBuiltinFunc(1, func(interp *Interpreter, i *LoxInstance, args ...interface{}) interface{} {
+			path := fsPath(interp, "readFile", args, 0)
+			chargeFile(interp, "readFile", path)
+
+			content, err := os.ReadFile(path)
+			if err != nil {
+				panic(fsError("readFile", err))
+			}
+			return string(content)
+		}),
+		"writeFile":  writeFile("writeFile", os.O_WRONLY|os.O_CREATE|os.O_TRUNC),
+		"appendFile": writeFile("appendFile", os.O_WRONLY|os.O_CREATE|os.O_APPEND),
+		"readLines": NewBuiltinFunc(1, func(interp *Interpreter, i *LoxInstance, args ...interface{}) interface{} {
+			path := fsPath(interp, "readLines", args, 0)
+			chargeFile(interp, "readLines", path)
+
+			content, err := os.ReadFile(path)
+			if err != nil {
+				panic(fsError("readLines", err))
+			}
+
+			lines := strings.Split(strings.TrimSuffix(string(content), "\n"), "\n")
+			if len(content) == 0 {
+				lines = nil
+			}
+			list := make([]interface{}, len(lines))
+			for index, line := range lines {
+				list[index] = strings.TrimSuffix(line, "\r")
+			}
+			return newArray(interp, list)
+		}),
+		// lines(path) streams the lines of a file through a LineReader.
+		"lines": NewBuiltinFunc(1, func(interp *Interpreter, i *LoxInstance, args ...interface{}) interface{} {
+			path := fsPath(interp, "lines", args, 0)
+			file, err := os.Open(path)
+			if err != nil {
+				panic(fsError("lines", err))
+			}
+			return newLineReader(interp, bufio.NewReader(file), file)
+		}),
+		"exists": NewBuiltinFunc(1, func(interp *Interpreter, i *LoxInstance, args ...interface{}) interface{} {
+			_, err := os.Stat(fsPath(interp, "exists", args, 0))
+			return err == nil
+		}),
+		// listDir(path) returns the sorted names of the entries in a directory.
+		"listDir": NewBuiltinFunc(1, func(interp *Interpreter, i *LoxInstance, args ...interface{}) interface{} {
+			entries, err := os.ReadDir(fsPath(interp, "listDir", args, 0))
+			if err != nil {
+				panic(fsError("listDir", err))
+			}
+
+			names := make([]string, len(entries))
+			for index, entry := range entries {
+				names[index] = entry.Name()
+			}
+			sort.Strings(names)
+
+			list := make([]interface{}, len(names))
+			for index, name := range names {
+				interp.alloc(sizeString + len(name))
+				list[index] = name
+			}
+			return newArray(interp, list)
+		}),
+		// mkdir(path) creates a directory along with any missing parents.
+		"mkdir": NewBuiltinFunc(1, func(interp *Interpreter, i *LoxInstance, args ...interface{}) interface{} {
+			if err := os.MkdirAll(fsPath(interp, "mkdir", args, 0), 0755); err != nil {
+				panic(fsError("mkdir", err))
+			}
+			return nil
+		}),
+		// remove(path) removes a file or an empty directory.
+		"remove": NewBuiltinFunc(1, func(interp *Interpreter, i *LoxInstance, args ...interface{}) interface{} {
+			if err := os.Remove(fsPath(interp, "remove", args, 0)); err != nil {
+				panic(fsError("remove", err))
+			}
+			return nil
+		}),
+		"stat": NewBuiltinFunc(1, func(interp *Interpreter, i *LoxInstance, args ...interface{}) interface{} {
+			info, err := os.Stat(fsPath(interp, "stat", args, 0))
+			if err != nil {
+				panic(fsError("stat", err))
+			}
+
+			interp.alloc(sizeInstance + 4*sizeSlot)
+			stat := NewLoxInstance(LoxStat)
+			stat.props["name"] = info.Name()
+			stat.props["size"] = int(info.Size())
+			stat.props["isDir"] = info.IsDir()
+			stat.props["modified"] = int(info.ModTime().UnixNano() / 1e6)
+			return stat
+		}),
+	}
+
+	LoxFS = NewLoxClass("fs", nil, statics, nil, nil, nil)
+	LoxStat = NewLoxClass("Stat", nil, nil, nil, nil, nil)
+}
@@ -0,0 +1,107 @@
+package lox
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func newFSInterpreter(t *testing.T) (*Interpreter, string) {
+	dir := t.TempDir()
+	interpreter := NewInterpreter(false)
+	if err := interpreter.AllowFS(dir); err != nil {
+		t.Fatal(err)
+	}
+	return interpreter, dir
+}
+
+func TestFSDisabled(t *testing.T) {
+	dir := t.TempDir()
+	interpreter := NewInterpreter(false)
+
+	if !runWith(t, interpreter, "fs.exists(\""+dir+"\");") {
+		t.Error("expect fs to be disabled by default.")
+	}
+}
+
+func TestFSReadWrite(t *testing.T) {
+	interpreter, dir := newFSInterpreter(t)
+	src := `
+	var dir = "` + dir + `"
+	var nl = "` + "\n" + `"
+	fs.mkdir(dir + "/logs/old")
+	fs.writeFile(dir + "/logs/a.txt", "first" + nl)
+	fs.appendFile(dir + "/logs/a.txt", "second" + nl)
+
+	var content = fs.readFile(dir + "/logs/a.txt")
+	var lines = fs.readLines(dir + "/logs/a.txt")
+	var entries = fs.listDir(dir + "/logs")
+	var stat = fs.stat(dir + "/logs/a.txt")
+
+	var streamed = []
+	var reader = fs.lines(dir + "/logs/a.txt")
+	for (var line = reader.next(); line != nil; line = reader.next()) {
+		streamed.append(line)
+	}
+
+	fs.remove(dir + "/logs/a.txt")
+	var exists = fs.exists(dir + "/logs/a.txt")
+	`
+	if runWith(t, interpreter, src) {
+		t.Fatalf("unexpected runtime error: %v", interpreter.LastError())
+	}
+
+	global := interpreter.global.values
+	if global["content"] != "first\nsecond\n" {
+		t.Errorf("unexpected content %q", global["content"])
+	}
+	if lines := arrayList(global["lines"].(*_arrayInsType).LoxInstance); len(lines) != 2 || lines[1] != "second" {
+		t.Errorf("unexpected lines %v", lines)
+	}
+	if entries := arrayList(global["entries"].(*_arrayInsType).LoxInstance); len(entries) != 2 || entries[0] != "a.txt" {
+		t.Errorf("unexpected entries %v", entries)
+	}
+	if stat := global["stat"].(*LoxInstance); stat.props["size"] != 13 || stat.props["isDir"] != false {
+		t.Errorf("unexpected stat %v", stat.props)
+	}
+	if streamed := arrayList(global["streamed"].(*_arrayInsType).LoxInstance); len(streamed) != 2 || streamed[0] != "first" {
+		t.Errorf("unexpected streamed lines %v", streamed)
+	}
+	if global["exists"] != false {
+		t.Error("expect the file to be removed.")
+	}
+}
+
+func TestFSConfinement(t *testing.T) {
+	interpreter, dir := newFSInterpreter(t)
+	outside := t.TempDir()
+
+	if !runWith(t, interpreter, "fs.readFile(\""+dir+"/../secret\");") {
+		t.Error("expect access outside the allowed root to be denied.")
+	}
+
+	if err := os.Symlink(outside, filepath.Join(dir, "link")); err != nil {
+		t.Skip("symlinks are not supported.")
+	}
+	if !runWith(t, interpreter, "fs.writeFile(\""+dir+"/link/escaped\", \"x\");") {
+		t.Error("expect access through a symlink to be denied.")
+	}
+	if _, err := os.Stat(filepath.Join(outside, "escaped")); err == nil {
+		t.Error("expect no file to be written outside the allowed root.")
+	}
+
+	if err := os.Symlink(filepath.Join(outside, "dangling"), filepath.Join(dir, "dangling")); err != nil {
+		t.Fatal(err)
+	}
+	for _, fn := range []string{"writeFile", "appendFile"} {
+		if !runWith(t, interpreter, "fs."+fn+"(\""+dir+"/dangling\", \"x\");") {
+			t.Errorf("expect fs.%v() through a dangling symlink to be denied.", fn)
+		}
+	}
+	if !runWith(t, interpreter, "fs.mkdir(\""+dir+"/dangling\");") {
+		t.Error("expect fs.mkdir() through a dangling symlink to be denied.")
+	}
+	if _, err := os.Lstat(filepath.Join(outside, "dangling")); err == nil {
+		t.Error("expect nothing to be created outside the allowed root through a dangling symlink.")
+	}
+}
@@ -4,23 +4,63 @@ package lox
 type LoxFunction struct {
 	Declaration *Function
 	Enclosing   *Environment
+	class       *LoxClass // the class the function is declared in, for accessing private members.
 }
 
 // NewLoxFunction returns a new lox runtime function.
 func NewLoxFunction(declaration *Function, enclosing *Environment) *LoxFunction {
-	return &LoxFunction{declaration, enclosing}
+	return &LoxFunction{Declaration: declaration, Enclosing: enclosing}
 }
 
-// Arity returns the number of args the lox function takes.
+// Arity returns the number of args the lox function takes at most, -1 if it has a rest parameter.
 func (f *LoxFunction) Arity() int {
-	return len(f.Declaration.Params)
+	_, max := f.Declaration.arity()
+	return max
+}
+
+// arity returns the least & the most number of args a function declaration takes.
+// The most is -1 if it has a rest parameter.
+func (f *Function) arity() (int, int) {
+	required := 0
+	for index := range f.Params {
+		if index < len(f.Defaults) && f.Defaults[index] != nil {
+			break
+		}
+		required++
+	}
+	if f.Rest != nil {
+		return required, -1
+	}
+	return required, len(f.Params)
+}
+
+// arityRange returns the least & the most number of args `callee` takes, the most
+// is -1 if there is no upper bound.
+func arityRange(callee Callable) (int, int) {
+	switch callee := callee.(type) {
+	case *LoxFunction:
+		return callee.Declaration.arity()
+	case *LoxClass:
+		if initializer := callee.findInit(); initializer != nil {
+			return arityRange(initializer)
+		}
+		return 0, 0
+	}
+
+	// variadic native functions check their args themselves.
+	if arity := callee.Arity(); arity != -1 {
+		return arity, arity
+	}
+	return 0, -1
 }
 
 // Bind adds a new scope containing "this", which is bound to the given LoxInstance.
 func (f *LoxFunction) Bind(instance *LoxInstance) Callable {
 	env := NewEnvironment(f.Enclosing)
 	env.Define("this", instance)
-	return NewLoxFunction(f.Declaration, env)
+	bound := NewLoxFunction(f.Declaration, env)
+	bound.class = f.class
+	return bound
 }
 
 // Call executes the function's body.
@@ -28,6 +68,13 @@ func (f *LoxFunction) Call(interpreter *Interpreter, arguments ...interface{}) (
 	enclosingEnv := interpreter.environment // for return usage.
 	env := NewEnvironment(f.Enclosing)
 
+	// the return value is evaluated in the context of the class as well.
+	enclosingClass := interpreter.currentClass
+	interpreter.currentClass = f.class
+	defer func() {
+		interpreter.currentClass = enclosingClass
+	}()
+
 	defer func() {
 		if val := recover(); val != nil {
 			returnControl, ok := val.(*Control)
@@ -47,7 +94,26 @@ func (f *LoxFunction) Call(interpreter *Interpreter, arguments ...interface{}) (
 	}()
 
 	for i, param := range f.Declaration.Params {
-		env.Define(param.Lexeme, arguments[i])
+		var value interface{}
+		if i < len(arguments) {
+			value = arguments[i]
+		} else if i < len(f.Declaration.Defaults) && f.Declaration.Defaults[i] != nil {
+			// defaults are evaluated in the scope of the previous parameters.
+			interpreter.environment = env
+			value = interpreter.evaluate(f.Declaration.Defaults[i])
+			interpreter.environment = enclosingEnv
+		}
+		interpreter.alloc(sizeSlot + len(param.Lexeme))
+		env.Define(param.Lexeme, value)
+	}
+
+	if rest := f.Declaration.Rest; rest != nil {
+		var values []interface{}
+		if len(arguments) > len(f.Declaration.Params) {
+			values = append(values, arguments[len(f.Declaration.Params):]...)
+		}
+		interpreter.alloc(sizeSlot + len(rest.Lexeme))
+		env.Define(rest.Lexeme, newArray(interpreter, values))
 	}
 
 	interpreter.executeBlock(f.Declaration.Body, env)
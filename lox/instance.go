@@ -2,8 +2,11 @@ package lox
 
 // LoxInstance represents a runtime object for lox instance.
 type LoxInstance struct {
-	class *LoxClass
-	props map[string]interface{}
+	class    *LoxClass
+	props    map[string]interface{}
+	privates map[privateKey]interface{} // private fields, see private.go.
+	frozen   bool                       // set by freeze(obj), see freeze.go.
+	native   interface{}                // the go value wrapped by instances of builtin classes, hidden from scripts.
 }
 
 // NewLoxInstance returns a runtime object.
@@ -15,6 +18,10 @@ func NewLoxInstance(class *LoxClass) *LoxInstance {
 // Get returns the requested field.
 // If the requested field is a getter, find the method, execute it and return the value.
 func (o *LoxInstance) Get(interpreter *Interpreter, name *Token) interface{} {
+	if isPrivate(name.Lexeme) {
+		return o.getPrivate(interpreter, name)
+	}
+
 	// property
 	if val, ok := o.props[name.Lexeme]; ok {
 		return val
@@ -22,6 +29,8 @@ func (o *LoxInstance) Get(interpreter *Interpreter, name *Token) interface{} {
 
 	// method
 	if meth := o.class.FindMethod(o, name.Lexeme); meth != nil {
+		// binding creates a closure.
+		interpreter.alloc(sizeEnv + sizeFunction)
 		return meth
 	}
 
@@ -35,24 +44,25 @@ func (o *LoxInstance) Get(interpreter *Interpreter, name *Token) interface{} {
 
 // Set sets a field to the given value.
 func (o *LoxInstance) Set(interpreter *Interpreter, name *Token, value interface{}) interface{} {
+	o.checkFrozen(name)
+	if isPrivate(name.Lexeme) {
+		return o.setPrivate(interpreter, name, value)
+	}
+
 	// setter.
 	if set := o.class.FindSetter(o, name.Lexeme); set != nil {
 		return set.Call(interpreter, value)
 	}
 
 	// property.
+	if _, ok := o.props[name.Lexeme]; !ok {
+		interpreter.alloc(sizeSlot + len(name.Lexeme))
+	}
 	o.props[name.Lexeme] = value
 	return value
 }
 
+// String formats the instance like print does, without calling toString().
 func (o *LoxInstance) String() string {
-	stringfied := "[" + o.class.String() + " instance" + "] {\n"
-
-	for name, value := range o.props {
-		valueStr, _ := value.(string)
-		stringfied += "\t" + name + ": " + valueStr + "\n"
-	}
-
-	stringfied += "}"
-	return stringfied
+	return formatValue(o)
 }
@@ -0,0 +1,112 @@
+package lox
+
+import (
+	"fmt"
+	"strings"
+)
+
+// Interfaces list the methods, with their arity, & the getters that a class must
+// provide, e.g. `class Foo < Bar implements Plugin`. The members may be declared
+// by the class, inherited from its superclass or composed from its traits.
+// The interpreter checks a class when it is declared, the resolver checks the
+// classes whose members are all known statically. `implements(value, Plugin)`
+// checks whether a class or the class of an instance provides the members.
+
+// LoxInterface is the runtime object for an interface.
+type LoxInterface struct {
+	Name        string
+	Declaration *Interface
+}
+
+// NewLoxInterface returns a runtime object for an interface.
+func NewLoxInterface(declaration *Interface) *LoxInterface {
+	return &LoxInterface{declaration.Name.Lexeme, declaration}
+}
+
+func (f *LoxInterface) String() string {
+	return "<interface " + f.Name + ">"
+}
+
+// memberLookup looks up the least & the most number of args a method takes, the
+// most being -1 if it is variadic, or a getter of a class.
+type memberLookup struct {
+	method func(name string) (min, max int, ok bool)
+	getter func(name string) bool
+}
+
+// unimplemented returns an error message for the first member of `iface` a class
+// named `class` doesn't provide, or an empty string if it provides all of them.
+func unimplemented(iface *Interface, class string, members memberLookup) string {
+	for _, method := range iface.Methods {
+		min, max, ok := members.method(method.Name.Lexeme)
+		if !ok {
+			params := make([]string, len(method.Params))
+			for index, param := range method.Params {
+				params[index] = param.Lexeme
+			}
+			return fmt.Sprintf("class %v must implement '%v(%v)' of %v.",
+				class, method.Name.Lexeme, strings.Join(params, ", "), iface.Name.Lexeme)
+		}
+		// the method must accept the args the interface accepts.
+		least, most := method.arity()
+		if min > least || (max != -1 && (most == -1 || max < most)) {
+			return fmt.Sprintf("'%v' of class %v must take %v arguments to implement %v, but it takes %v.",
+				method.Name.Lexeme, class, arityString(least, most), iface.Name.Lexeme, arityString(min, max))
+		}
+	}
+
+	for _, getter := range iface.Getters {
+		if !members.getter(getter.Name.Lexeme) {
+			return fmt.Sprintf("class %v must implement getter '%v' of %v.",
+				class, getter.Name.Lexeme, iface.Name.Lexeme)
+		}
+	}
+	return ""
+}
+
+// members returns the lookup of the methods & getters of the class, which might be inherited or composed.
+func (c *LoxClass) members() memberLookup {
+	return memberLookup{
+		method: func(name string) (int, int, bool) {
+			for class := c; class != nil; class = class.parent() {
+				if method, ok := class.Methods[name]; ok {
+					min, max := arityRange(method)
+					return min, max, true
+				}
+			}
+			return 0, 0, false
+		},
+		getter: func(name string) bool {
+			for class := c; class != nil; class = class.parent() {
+				if _, ok := class.Getters[name]; ok {
+					return true
+				}
+			}
+			return false
+		},
+	}
+}
+
+// defineImplements defines `implements(value, interface)`, which reports whether
+// `value`, a class or an instance, provides the members of `interface`.
+func (i *Interpreter) defineImplements() {
+	i.global.Define("implements", NewBuiltinFunc(2, func(interp *Interpreter, _ *LoxInstance, args ...interface{}) interface{} {
+		iface, ok := args[1].(*LoxInterface)
+		if !ok {
+			panic(NewRuntimeError(nil, "implements() expects an interface as the second argument."))
+		}
+
+		var class *LoxClass
+		switch value := args[0].(type) {
+		case *LoxClass:
+			class = value
+		case *LoxInstance:
+			class = value.class
+		case *_arrayInsType:
+			class = value.class
+		default:
+			return false
+		}
+		return unimplemented(iface.Declaration, class.Name, class.members()) == ""
+	}))
+}
@@ -1,18 +1,35 @@
 package lox
 
 import (
+	"bufio"
 	"fmt"
+	"math/rand/v2"
+	"os"
+	"time"
 
 	"github.com/fatih/color"
 )
 
 // Interpreter is an object interprets our AST.
 type Interpreter struct {
-	repl            bool         // REPL mode or not.
-	hadRuntimeError bool         // indicates runtime error.
-	environment     *Environment // current environment.
-	global          *Environment // global environment.
-	locals          map[Expr]int // for local variable resolution.
+	repl            bool                  // REPL mode or not.
+	hadRuntimeError bool                  // indicates runtime error.
+	environment     *Environment          // current environment.
+	global          *Environment          // global environment.
+	locals          map[Expr]int          // for local variable resolution.
+	frames          []*Environment        // environments of the enclosing blocks and calls.
+	memory          memory                // memory accounting.
+	lastError       error                 // the last runtime error.
+	fsRoots         []string              // directories scripts are allowed to access.
+	osModule        *LoxClass             // the os module of this interpreter, holding its args.
+	stdin           *bufio.Reader         // where scripts read their input from.
+	exitError       *ExitError            // set once the script calls os.exit.
+	clock           Clock                 // where the time comes from.
+	started         time.Time             // when the interpreter started, per clock.
+	rand            *rand.Rand            // the default random generator, see Random.
+	formatting      map[*LoxInstance]bool // instances being formatted, for detecting cycles.
+	currentClass    *LoxClass             // the class of the running method, for accessing private members.
+	assignments     []assignment          // the assignments of the destructuring being matched.
 }
 
 // NewInterpreter returns an interpreter object.
@@ -22,22 +39,72 @@ func NewInterpreter(repl bool) *Interpreter {
 
 	initArray()
 	global.Define("Array", LoxArray)
-
-	return &Interpreter{
+	initMath()
+	global.Define("Math", LoxMath)
+	initString()
+	global.Define("String", LoxString)
+	initFS()
+	global.Define("fs", LoxFS)
+	initJSON()
+	global.Define("JSON", LoxJSON)
+	global.Define("Object", LoxObject)
+	initTime()
+	global.Define("Time", LoxTime)
+	global.Define("DateTime", LoxDateTime)
+	initRegex()
+	global.Define("Regex", LoxRegex)
+	initRandom()
+	global.Define("Random", LoxRandom)
+	initReflect()
+	global.Define("Reflect", LoxReflect)
+	initLineReader()
+	initOS()
+
+	interpreter := &Interpreter{
 		repl:            repl,
 		hadRuntimeError: false,
 		environment:     global,
 		global:          environment,
 		locals:          map[Expr]int{},
+		stdin:           bufio.NewReader(os.Stdin),
+		clock:           systemClock{},
+		started:         time.Now(),
+	}
+	interpreter.defineClock()
+	interpreter.defineProcess()
+	interpreter.defineImplements()
+	interpreter.defineTypes()
+	interpreter.defineFreeze()
+	// builtin functions are named after the globals defining them.
+	for name, value := range global.values {
+		if fn, ok := value.(*BuiltInFunc); ok && fn.name == "" {
+			fn.name = name
+		}
 	}
+	return interpreter
 }
 
 func (i *Interpreter) Interprete(stmts []Stmt) (hadRuntimeError bool) {
+	i.hadRuntimeError = false
 	defer func() {
 		if val := recover(); val != nil {
-			runtimeError := val.(*RuntimeError)
+			// unwind the environments left by the interrupted statement.
+			i.environment = i.global
+			i.frames = i.frames[:0]
+			i.currentClass = nil
+
+			// os.exit is not an error.
+			if exitError, ok := val.(*ExitError); ok {
+				i.exitError = exitError
+				hadRuntimeError = i.hadRuntimeError
+				return
+			}
+
+			// might trigger another panic if it is not a RuntimeError or OutOfMemoryError.
+			runtimeError := val.(error)
 			fmt.Println(runtimeError.Error())
 			i.hadRuntimeError = true
+			i.lastError = runtimeError
 		}
 		hadRuntimeError = i.hadRuntimeError
 	}()
@@ -56,11 +123,23 @@ func (i *Interpreter) execute(stmt Stmt) {
 	stmt.Accept(i)
 }
 
+// LastError returns the last runtime error reported by Interprete, e.g. an *OutOfMemoryError.
+func (i *Interpreter) LastError() error {
+	return i.lastError
+}
+
 // execute a block in `env`.
 func (i *Interpreter) executeBlock(stmts []Stmt, env *Environment) {
 	prevEnv := i.environment
+	i.alloc(sizeEnv)
 	i.environment = env
 
+	// keep the enclosing environment reachable for memory recounts.
+	i.frames = append(i.frames, prevEnv)
+	defer func() {
+		i.frames = i.frames[:len(i.frames)-1]
+	}()
+
 	for _, stmt := range stmts {
 		i.execute(stmt)
 	}
@@ -86,16 +165,46 @@ func (i *Interpreter) VisitClassStmt(stmt *Class) interface{} {
 		if superClass, ok = super.(*LoxClass); ok != true {
 			panic(NewRuntimeError(stmt.Super.Name, "superclass must be a class."))
 		}
+		if superClass.enum != nil {
+			panic(NewRuntimeError(stmt.Super.Name, "cannot inherit from enum '"+superClass.Name+"'."))
+		}
+	}
+
+	traits := make([]*LoxTrait, 0, len(stmt.Traits))
+	for _, name := range stmt.Traits {
+		trait, ok := i.evaluate(name).(*LoxTrait)
+		if !ok {
+			panic(NewRuntimeError(name.Name, "a class can only be composed with traits."))
+		}
+		traits = append(traits, trait)
+	}
+
+	interfaces := make([]*LoxInterface, 0, len(stmt.Interfaces))
+	for _, name := range stmt.Interfaces {
+		iface, ok := i.evaluate(name).(*LoxInterface)
+		if !ok {
+			panic(NewRuntimeError(name.Name, "a class can only implement interfaces."))
+		}
+		interfaces = append(interfaces, iface)
 	}
 
 	i.environment.Define(stmt.Name.Lexeme, nil)
 
-	if stmt.Super != nil {
+	// "super" refers to the members of the traits first.
+	super := superClass
+	if len(traits) > 0 {
+		super = i.composeTraits(stmt, superClass, traits)
+	}
+
+	if stmt.Super != nil || len(traits) > 0 {
 		// add another scope for "super".
 		i.environment = NewEnvironment(i.environment)
-		i.environment.Define("super", superClass)
+		i.environment.Define("super", super)
 	}
 
+	i.alloc(sizeClass + sizeFunction*(len(stmt.Statics)+len(stmt.Methods)+len(stmt.Getters)+len(stmt.Setters)+
+		len(stmt.StaticGetters)+len(stmt.StaticSetters)))
+
 	statics := map[string]Callable{}
 	for _, static := range stmt.Statics {
 		statics[static.Name.Lexeme] = NewLoxFunction(static, i.environment)
@@ -117,17 +226,76 @@ func (i *Interpreter) VisitClassStmt(stmt *Class) interface{} {
 	}
 
 	class := NewLoxClass(stmt.Name.Lexeme, superClass, statics, methods, getters, setters)
+	class.StaticGetters = map[string]Callable{}
+	for _, getter := range stmt.StaticGetters {
+		class.StaticGetters[getter.Name.Lexeme] = NewLoxFunction(getter, i.environment)
+	}
+	class.StaticSetters = map[string]Callable{}
+	for _, setter := range stmt.StaticSetters {
+		class.StaticSetters[setter.Name.Lexeme] = NewLoxFunction(setter, i.environment)
+	}
+	class.declared = true
+	if len(traits) > 0 {
+		class.Traits = traits
+		class.traits = super
+	}
 
-	if stmt.Super != nil {
-		// remember to exist the scope created previously,
-		// before assigning the current class to current env.
-		i.environment = i.environment.enclosing
+	for _, iface := range interfaces {
+		if message := unimplemented(iface.Declaration, class.Name, class.members()); message != "" {
+			panic(NewRuntimeError(stmt.Name, message))
+		}
+	}
+	if len(interfaces) > 0 {
+		class.Interfaces = interfaces
+	}
+
+	// methods run in the context of the class, for accessing private members.
+	tables := []map[string]Callable{statics, methods, getters, setters, class.StaticGetters, class.StaticSetters}
+	if class.traits != nil {
+		tables = append(tables, class.traits.Methods, class.traits.Getters, class.traits.Setters)
+	}
+	for _, table := range tables {
+		for _, fn := range table {
+			fn.(*LoxFunction).class = class
+		}
 	}
 
+	// the class is available to its static initializers.
 	i.environment.Assign(stmt.Name, class)
+	i.initStatics(class, stmt.Initializers)
+
+	if stmt.Super != nil || len(traits) > 0 {
+		// remember to exist the scope created previously.
+		i.environment = i.environment.enclosing
+	}
 	return nil
 }
 
+// initStatics runs the static field initializers & the static blocks of `class` in order.
+func (i *Interpreter) initStatics(class *LoxClass, initializers []Stmt) {
+	for _, initializer := range initializers {
+		switch stmt := initializer.(type) {
+		case *Var:
+			i.initStaticField(class, stmt)
+		case *VarList:
+			for _, field := range stmt.stmts {
+				i.initStaticField(class, field)
+			}
+		case *Block:
+			i.executeBlock(stmt.Stmts, NewEnvironment(i.environment))
+		}
+	}
+}
+
+func (i *Interpreter) initStaticField(class *LoxClass, field *Var) {
+	var value interface{}
+	if field.Initializer != nil {
+		value = i.evaluate(field.Initializer)
+	}
+	i.alloc(sizeSlot + len(field.Name.Lexeme))
+	class.Fields[field.Name.Lexeme] = value
+}
+
 func (i *Interpreter) VisitControlStmt(stmt *Control) interface{} {
 	// throw it to unwind the call stack.
 	panic(stmt)
@@ -139,7 +307,7 @@ func (i *Interpreter) VisitExpressionStmt(stmt *Expression) interface{} {
 		if val == nil {
 			return nil
 		}
-		color.Cyan("%v", val)
+		color.Cyan("%v", i.stringify(val))
 	}
 	return nil
 }
@@ -147,7 +315,10 @@ func (i *Interpreter) VisitExpressionStmt(stmt *Expression) interface{} {
 // VisitFunctionStmt converts function ast node to runtime function object.
 // This function adds an entry to the current env, while methods in a class don't.
 func (i *Interpreter) VisitFunctionStmt(stmt *Function) interface{} {
-	i.environment.Define(stmt.Name.Lexeme, NewLoxFunction(stmt, i.environment))
+	i.alloc(sizeFunction + sizeSlot + len(stmt.Name.Lexeme))
+	function := NewLoxFunction(stmt, i.environment)
+	function.class = i.currentClass
+	i.environment.Define(stmt.Name.Lexeme, function)
 	return nil
 }
 
@@ -164,7 +335,7 @@ func (i *Interpreter) VisitIfStmt(stmt *If) interface{} {
 // VisitPrintStmt prints an expression in Cyan color.
 func (i *Interpreter) VisitPrintStmt(stmt *Print) interface{} {
 	val := i.evaluate(stmt.Expression)
-	color.Cyan("%v", val)
+	color.Cyan("%v", i.stringify(val))
 	return nil
 }
 
@@ -180,7 +351,12 @@ func (i *Interpreter) VisitVarStmt(stmt *Var) interface{} {
 		initVal = i.evaluate(initializer)
 	}
 
-	i.environment.Define(identifier.Lexeme, initVal)
+	i.alloc(sizeSlot + len(identifier.Lexeme))
+	if stmt.Constant {
+		i.environment.DefineConst(identifier.Lexeme, initVal)
+	} else {
+		i.environment.Define(identifier.Lexeme, initVal)
+	}
 	return nil
 }
 
@@ -194,6 +370,20 @@ func (i *Interpreter) VisitVarListStmt(stmt *VarList) interface{} {
 	return nil
 }
 
+// VisitInterfaceStmt converts an interface declaration to a runtime interface object.
+func (i *Interpreter) VisitInterfaceStmt(stmt *Interface) interface{} {
+	i.alloc(sizeClass + sizeSlot + len(stmt.Name.Lexeme))
+	i.environment.Define(stmt.Name.Lexeme, NewLoxInterface(stmt))
+	return nil
+}
+
+// VisitTraitStmt converts a trait declaration to a runtime trait object.
+func (i *Interpreter) VisitTraitStmt(stmt *Trait) interface{} {
+	i.alloc(sizeClass + sizeSlot + len(stmt.Name.Lexeme))
+	i.environment.Define(stmt.Name.Lexeme, NewLoxTrait(stmt, i.environment))
+	return nil
+}
+
 func (i *Interpreter) VisitWhileStmt(stmt *While) interface{} {
 	defer func() {
 		if val := recover(); val != nil {
@@ -217,40 +407,62 @@ func (i *Interpreter) VisitWhileStmt(stmt *While) interface{} {
 }
 
 func (i *Interpreter) VisitArrayExpr(expr *Array) interface{} {
-	elemValues := make([]interface{}, 0)
-	for _, elem := range expr.Elements {
-		value := i.evaluate(elem)
-		elemValues = append(elemValues, value)
+	return LoxArray.Call(i, i.spread(expr.Elements)...)
+}
+
+// spread evaluates the arguments of a call or the elements of an array literal,
+// in which `...array` is replaced by the elements of `array`.
+func (i *Interpreter) spread(exprs []Expr) []interface{} {
+	values := make([]interface{}, 0, len(exprs))
+	for _, expr := range exprs {
+		spread, ok := expr.(*Spread)
+		if !ok {
+			values = append(values, i.evaluate(expr))
+			continue
+		}
+
+		array, ok := i.evaluate(spread.Expression).(*_arrayInsType)
+		if !ok {
+			panic(NewRuntimeError(spread.Ellipsis, "only arrays can be spread."))
+		}
+		values = append(values, arrayList(array.LoxInstance)...)
 	}
-	return LoxArray.Call(i, elemValues...)
+	return values
 }
 
-func (i *Interpreter) VisitAssignExpr(expr *Assign) interface{} {
-	var operator TokenType
+// VisitSpreadExpr is only reached by spreads out of calls & array literals, which the parser rejects.
+func (i *Interpreter) VisitSpreadExpr(expr *Spread) interface{} {
+	panic(NewRuntimeError(expr.Ellipsis, "unexpected spread."))
+}
 
-	value := i.evaluate(expr.Value)
-	switch expr.Operator.Type {
-	case TokenPlusEqual:
-		operator = TokenPlus
-	case TokenMinusEqual:
-		operator = TokenMinus
-	case TokenStarEqual:
-		operator = TokenStar
-	case TokenSlashEqual:
-		operator = TokenSlash
-	case TokenPercentEqual:
-		operator = TokenPercent
-	default:
-		operator = 0
+// compoundOperators maps compound assignment operators to the binary operators they apply.
+var compoundOperators = map[TokenType]TokenType{
+	TokenPlusEqual:    TokenPlus,
+	TokenMinusEqual:   TokenMinus,
+	TokenStarEqual:    TokenStar,
+	TokenSlashEqual:   TokenSlash,
+	TokenPercentEqual: TokenPercent,
+}
+
+// compound returns the value assigned by `operator`, e.g. `current() + value` for `+=`.
+// `current` reads the value of the target, it is only called by compound operators.
+func (i *Interpreter) compound(operator *Token, current func() interface{}, value interface{}) interface{} {
+	binaryOperator, ok := compoundOperators[operator.Type]
+	if !ok {
+		return value
 	}
 
-	if operator != 0 {
-		lval := NewLiteral(i.lookUpVariable(expr, expr.Name))
-		rval := NewLiteral(value)
+	lval := NewLiteral(current())
+	rval := NewLiteral(value)
 
-		binary, _ := NewBinary(lval, NewToken(operator, "", nil, expr.Operator.Line), rval).(*Binary)
-		value = i.VisitBinaryExpr(binary)
-	}
+	binary, _ := NewBinary(lval, NewToken(binaryOperator, "", nil, operator.Line), rval).(*Binary)
+	return i.VisitBinaryExpr(binary)
+}
+
+func (i *Interpreter) VisitAssignExpr(expr *Assign) interface{} {
+	value := i.compound(expr.Operator, func() interface{} {
+		return i.lookUpVariable(expr, expr.Name)
+	}, i.evaluate(expr.Value))
 
 	distance, ok := i.locals[expr]
 	if ok {
@@ -265,6 +477,15 @@ func (i *Interpreter) VisitBinaryExpr(expr *Binary) interface{} {
 	left := i.evaluate(expr.Left)
 	right := i.evaluate(expr.Right)
 
+	if expr.Operator.Type == TokenIs {
+		return isInstance(expr.Operator, left, right)
+	}
+
+	// classes might overload the operator.
+	if result, ok := i.overloadBinary(expr.Operator, left, right); ok {
+		return result
+	}
+
 	var lval, rval float64
 	var bothInt bool
 
@@ -294,11 +515,13 @@ func (i *Interpreter) VisitBinaryExpr(expr *Binary) interface{} {
 		}
 		return lval - rval
 	case TokenPlus:
-		// string concat is supported.
-		lvalString, ok1 := left.(string)
-		rvalString, ok2 := right.(string)
-		if ok1 == true && ok2 == true {
-			return lvalString + rvalString
+		// string concat is supported, the other operand is converted into a string.
+		_, ok1 := left.(string)
+		_, ok2 := right.(string)
+		if ok1 == true || ok2 == true {
+			concat := i.stringify(left) + i.stringify(right)
+			i.alloc(sizeString + len(concat))
+			return concat
 		}
 
 		if lval, rval, bothInt = convertFloatOperands(expr.Operator, left, right); bothInt == true {
@@ -327,32 +550,75 @@ func (i *Interpreter) VisitBinaryExpr(expr *Binary) interface{} {
 
 func (i *Interpreter) VisitCallExpr(expr *Call) interface{} {
 	callee := i.evaluate(expr.Callee)
+	if expr.Optional && callee == nil {
+		panic(chainNil{})
+	}
 
 	function, ok := callee.(Callable)
 	if ok != true {
 		panic(NewRuntimeError(expr.Paren, "callee is not callable."))
 	}
 
-	// if it is -1, we don't check it.
-	if len(expr.Arguments) != function.Arity() && function.Arity() != -1 {
-		panic(NewRuntimeError(expr.Paren, fmt.Sprintf("expect %v arguments, but got %v", function.Arity(), len(expr.Arguments))))
+	// spread arguments are only counted once evaluated.
+	args := i.spread(expr.Arguments)
+	if min, max := arityRange(function); len(args) < min || (max != -1 && len(args) > max) {
+		panic(NewRuntimeError(expr.Paren, fmt.Sprintf("expect %v arguments, but got %v", arityString(min, max), len(args))))
 	}
 
-	args := make([]interface{}, 0)
-	for _, arg := range expr.Arguments {
-		args = append(args, i.evaluate(arg))
+	if _, ok := function.(*BuiltInFunc); ok {
+		// native functions don't know where they are called.
+		defer func() {
+			if val := recover(); val != nil {
+				if runtimeError, ok := val.(*RuntimeError); ok && runtimeError.token == nil {
+					runtimeError.token = expr.Paren
+				}
+				panic(val)
+			}
+		}()
 	}
 
 	return function.Call(i, args...)
 }
 
+// chainNil is thrown by an optional link of a chain, e.g. `a?.b`, whose object is
+// nil. It is recovered by the chain, which then evaluates to nil.
+type chainNil struct{}
+
+// VisitChainExpr interpretes a chain of calls, gets & subscripts having optional
+// links, which short-circuit the rest of the chain if their object is nil.
+func (i *Interpreter) VisitChainExpr(expr *Chain) (value interface{}) {
+	defer func() {
+		if val := recover(); val != nil {
+			if _, ok := val.(chainNil); !ok {
+				panic(val)
+			}
+			value = nil
+		}
+	}()
+	return i.evaluate(expr.Expression)
+}
+
+// VisitConditionalExpr interpretes `condition ? then : else`, only one of the branches is evaluated.
+func (i *Interpreter) VisitConditionalExpr(expr *Conditional) interface{} {
+	if truthy(i.evaluate(expr.Condition)) {
+		return i.evaluate(expr.ThenBranch)
+	}
+	return i.evaluate(expr.ElseBranch)
+}
+
 func (i *Interpreter) VisitGetExpr(expr *Get) interface{} {
-	if object, ok := i.evaluate(expr.Object).(ObjectType); ok {
+	value := i.evaluate(expr.Object)
+	if expr.Optional && value == nil {
+		panic(chainNil{})
+	}
+
+	if object, ok := value.(ObjectType); ok {
 		return object.Get(i, expr.Name)
 	}
 
-	if class, ok := i.evaluate(expr.Object).(*LoxClass); ok {
-		return class.FindStatic(expr.Name.Lexeme)
+	// strings dispatch to the String class.
+	if s, ok := value.(string); ok {
+		return newStringInstance(s).Get(i, expr.Name)
 	}
 
 	panic(NewRuntimeError(expr.Name, "unexpected property access."))
@@ -364,7 +630,10 @@ func (i *Interpreter) VisitGroupingExpr(expr *Grouping) interface{} {
 }
 
 func (i *Interpreter) VisitLambdaExpr(expr *Lambda) interface{} {
-	return NewLoxFunction(expr.LambdaFunc, i.environment)
+	i.alloc(sizeFunction)
+	lambda := NewLoxFunction(expr.LambdaFunc, i.environment)
+	lambda.class = i.currentClass
+	return lambda
 }
 
 func (i *Interpreter) VisitLiteralExpr(expr *Literal) interface{} {
@@ -374,7 +643,11 @@ func (i *Interpreter) VisitLiteralExpr(expr *Literal) interface{} {
 func (i *Interpreter) VisitLogicalExpr(expr *Logical) interface{} {
 	left := i.evaluate(expr.Left)
 
-	if expr.Operator.Type == TokenOr {
+	if expr.Operator.Type == TokenQuestionQuestion {
+		if left != nil {
+			return left
+		}
+	} else if expr.Operator.Type == TokenOr {
 		if truthy(left) {
 			return left
 		}
@@ -388,47 +661,77 @@ func (i *Interpreter) VisitLogicalExpr(expr *Logical) interface{} {
 }
 
 func (i *Interpreter) VisitSetExpr(expr *Set) interface{} {
-	var (
-		loxInstance ObjectType
-		value       interface{}
-		ok          bool
-	)
+	object := i.evaluate(expr.Object)
 
-	value = i.evaluate(expr.Object)
+	// TODO: fix this fake token.
+	if expr.Name.Type == -1 {
+		key, _ := expr.Name.Literal.(Expr)
+		return i.setSubscript(expr, object, i.evaluate(key))
+	}
 
-	if loxInstance, ok = value.(ObjectType); ok != true {
+	loxInstance, ok := object.(ObjectType)
+	if ok != true {
 		panic(NewRuntimeError(expr.Name, "set property on a non Lox instance object."))
 	}
 
-	// TODO: fix this fake token.
-	if expr.Name.Type == -1 {
-		name, _ := expr.Name.Literal.(Expr)
-		key := i.evaluate(name)
-		switch val := key.(type) {
-		case string:
-			// set a property of an object.
-			expr.Name.Lexeme = val
-		case int:
-			// access the array.
-			arrayObj, ok := i.evaluate(expr.Object).(*_arrayInsType)
-			if ok != true {
-				panic(NewRuntimeError(expr.Name, "unexpected index access."))
+	value := i.compound(expr.Operator, func() interface{} {
+		return loxInstance.Get(i, expr.Name)
+	}, i.evaluate(expr.Value))
+	loxInstance.Set(i, expr.Name, value)
+	return value
+}
+
+// setSubscript interpretes something like "object[key] = value".
+func (i *Interpreter) setSubscript(expr *Set, object, key interface{}) interface{} {
+	value := i.compound(expr.Operator, func() interface{} {
+		return i.subscript(expr.Name, object, key)
+	}, i.evaluate(expr.Value))
+
+	if method := i.findOperator(object, "__setitem__"); method != nil {
+		i.callOperator(expr.Name, method, "__setitem__", key, value)
+		return value
+	}
+
+	switch k := key.(type) {
+	case string:
+		checkSubscriptKey(expr.Name, k)
+		// set a property of an object.
+		if loxInstance, ok := object.(ObjectType); ok {
+			loxInstance.Set(i, NewToken(TokenIdentifier, k, nil, expr.Name.Line), value)
+			return value
+		}
+	case int:
+		// access the array.
+		if arrayObj, ok := object.(*_arrayInsType); ok {
+			arrayObj.checkFrozen(expr.Name)
+			list := arrayList(arrayObj.LoxInstance)
+			if k < 0 || k >= len(list) {
+				panic(NewRuntimeError(expr.Name, "index out of range."))
 			}
-			list := arrayObj.props["list"].([]interface{})
-			list[val] = value
+			list[k] = value
+			return value
 		}
 	}
 
-	value = i.evaluate(expr.Value)
-	loxInstance.Set(i, expr.Name, value)
-	return value
+	panic(NewRuntimeError(expr.Name, "invalid subscript assignment."))
 }
 
 func (i *Interpreter) VisitSubscriptExpr(expr *Subscript) interface{} {
-	key := i.evaluate(expr.Key)
 	object := i.evaluate(expr.Object)
+	if expr.Optional && object == nil {
+		panic(chainNil{})
+	}
+	return i.subscript(expr.Bracket, object, i.evaluate(expr.Key))
+}
+
+// subscript interpretes something like "object[key]".
+func (i *Interpreter) subscript(bracket *Token, object, key interface{}) interface{} {
+	if method := i.findOperator(object, "__getitem__"); method != nil {
+		return i.callOperator(bracket, method, "__getitem__", key)
+	}
 
 	if name, ok := key.(string); ok {
+		checkSubscriptKey(bracket, name)
 		switch obj := object.(type) {
 		case *LoxInstance:
 			if val, ok := obj.props[name]; ok {
@@ -441,29 +744,76 @@ func (i *Interpreter) VisitSubscriptExpr(expr *Subscript) interface{} {
 		}
 	} else if index, ok := key.(int); ok {
 		if arrayObj, ok := object.(*_arrayInsType); ok {
-			list, _ := arrayObj.props["list"].([]interface{})
+			list, _ := arrayObj.native.([]interface{})
+			if index < 0 || index >= len(list) {
+				panic(NewRuntimeError(bracket, "index out of range."))
+			}
 			return list[index]
 		}
 	}
 
-	panic(NewRuntimeError(expr.Bracket, "invalid subscript expression."))
+	panic(NewRuntimeError(bracket, "invalid subscript expression."))
 }
 
-// VisitSuperExpr interpretes something like "super.foo"
-func (i *Interpreter) VisitSuperExpr(expr *Super) interface{} {
-	distance := i.locals[expr]
+// superContext returns the superclass referenced by `super` at `distance`, and the instance
+// the enclosing method is bound to, which is nil in static methods.
+func (i *Interpreter) superContext(keyword *Token, distance int) (*LoxClass, *LoxInstance) {
 	superClass, _ := i.environment.GetAt(distance, "super").(*LoxClass)
-	// the context for the method queryed. This is a little hack since we've known
-	// it is there, and we've known it must be a LoxInstance.
+	// the scope right inside the one of "super" holds "this" in methods. In static
+	// methods, it is the scope of the function, where "this" is not defined.
 	object, _ := i.environment.GetAt(distance-1, "this").(*LoxInstance)
-	// TODO: add getter/setter inheritance support.
-	method := superClass.FindMethod(object, expr.Method.Lexeme)
+	if object == nil && superClass != nil && superClass.mixin {
+		// traits have no static members.
+		superClass = superClass.Super
+	}
+	if superClass == nil {
+		// a trait composed by a class without superclass.
+		panic(NewRuntimeError(keyword, "no superclass to refer to by 'super'."))
+	}
+	return superClass, object
+}
 
-	if method == nil {
-		panic(NewRuntimeError(expr.Method, "undefined property '"+expr.Method.Lexeme+"'."))
+// VisitSuperExpr interpretes something like "super.foo"
+func (i *Interpreter) VisitSuperExpr(expr *Super) interface{} {
+	superClass, object := i.superContext(expr.Keyword, i.locals[expr])
+	return i.superGet(superClass, object, expr.Method)
+}
+
+// superGet returns the property `name` of the superclass, for `object` if it isn't nil.
+func (i *Interpreter) superGet(superClass *LoxClass, object *LoxInstance, name *Token) interface{} {
+	// static properties of the superclass.
+	if object == nil {
+		return superClass.Get(i, name)
+	}
+
+	if method := superClass.FindMethod(object, name.Lexeme); method != nil {
+		return method
+	}
+	if getter := superClass.FindGetter(object, name.Lexeme); getter != nil {
+		return getter.Call(i, nil)
+	}
+
+	panic(NewRuntimeError(name, "undefined property '"+name.Lexeme+"'."))
+}
+
+// VisitSuperSetExpr interpretes something like "super.foo = value", which calls the setter of the superclass.
+func (i *Interpreter) VisitSuperSetExpr(expr *SuperSet) interface{} {
+	superClass, object := i.superContext(expr.Keyword, i.locals[expr])
+	value := i.compound(expr.Operator, func() interface{} {
+		return i.superGet(superClass, object, expr.Name)
+	}, i.evaluate(expr.Value))
+
+	// static properties of the superclass.
+	if object == nil {
+		return superClass.Set(i, expr.Name, value)
 	}
 
-	return method
+	if setter := superClass.FindSetter(object, expr.Name.Lexeme); setter != nil {
+		setter.Call(i, value)
+		return value
+	}
+
+	panic(NewRuntimeError(expr.Name, "undefined setter '"+expr.Name.Lexeme+"'."))
 }
 
 func (i *Interpreter) VisitThisExpr(expr *This) interface{} {
@@ -473,6 +823,10 @@ func (i *Interpreter) VisitThisExpr(expr *This) interface{} {
 func (i *Interpreter) VisitUnaryExpr(expr *Unary) interface{} {
 	operator := expr.Operator
 	value := i.evaluate(expr.Right)
+	if result, ok := i.overloadUnary(operator, value); ok {
+		return result
+	}
+
 	switch operator.Type {
 	case TokenMinus:
 		num, isInt := convertNumberOperand(operator, value)
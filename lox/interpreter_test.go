@@ -3,6 +3,7 @@ package lox
 import (
 	"fmt"
 	"math"
+	"strings"
 	"testing"
 )
 
@@ -61,6 +62,20 @@ func runStmt(t *testing.T, src string) {
 	}
 }
 
+// runResult evaluates `expr` with bindings resolved, as opposed to runExpr.
+// `src` is run first if given.
+func runResult(t *testing.T, src, expr string, expectedVal interface{}) {
+	interpreter := NewInterpreter(false)
+	if runWith(t, interpreter, src+"\nvar result = "+expr+";") {
+		t.Errorf("unexpected runtime error evaluating %v.", expr)
+		return
+	}
+
+	if value := interpreter.global.values["result"]; value != expectedVal {
+		t.Errorf("expect %v to be %v, but got %v", expr, expectedVal, value)
+	}
+}
+
 // ==================================== specific error runner ===================================
 // These are runners for testing specific errors, `src` passed to them should be ensured to have
 // specific errors, therefore some error checking are stripped.
@@ -118,7 +133,7 @@ func runRuntimeErrStmt(t *testing.T, src string) {
 // ================================= Error handler testing ================================
 func TestSynError(t *testing.T) {
 	runSynErrStmt(t, "true - true;")
-	runSynErrStmt(t, "1 + \"a string\";")
+	runSynErrStmt(t, "1 + true;")
 	runSynErrStmt(t, "-\"a string\";")
 	runSynErrStmt(t, "5.0 % 2;")
 	runSynErrStmt(t, "\"a string\" % \"another string\";")
@@ -247,3 +262,927 @@ func TestAssignExpr(t *testing.T) {
 	runStmt(t, "var a = \"head\"; a += \" tail\"; print a;")
 	runStmt(t, "var b = 1; b -= 1; print b;")
 }
+
+func TestMath(t *testing.T) {
+	runExpr(t, "Math.floor(2.7)", 2)
+	runExpr(t, "Math.floor(-2.5)", -3)
+	runExpr(t, "Math.ceil(2.1)", 3)
+	runExpr(t, "Math.round(2.5)", 3)
+	runExpr(t, "Math.floor(7)", 7)
+	runExpr(t, "Math.floor(Math.pow(2, 70) + 0.5)", math.Pow(2, 70))
+	runExpr(t, "Math.ceil(-Math.pow(2, 70))", -math.Pow(2, 70))
+	runExpr(t, "Math.abs(-3)", 3)
+	runExpr(t, "Math.abs(-3.5)", 3.5)
+	runExpr(t, "Math.sqrt(16)", 4.0)
+	runExpr(t, "Math.pow(2, 10)", 1024)
+	runExpr(t, "Math.pow(2, -1)", 0.5)
+	runExpr(t, "Math.pow(2.0, 2)", 4.0)
+	runExpr(t, "Math.min(3, 1.5, 2)", 1.5)
+	runExpr(t, "Math.max(3, 1.5, 7)", 7)
+	runExpr(t, "Math.floor(Math.PI * 100)", 314)
+	runExpr(t, "Math.exp(0)", 1.0)
+	runExpr(t, "Math.isNaN(Math.NaN)", true)
+	runExpr(t, "Math.isNaN(1)", false)
+	runExpr(t, "Math.isInteger(2)", true)
+	runExpr(t, "Math.isInteger(2.0)", true)
+	runExpr(t, "Math.isInteger(2.5)", false)
+	runExpr(t, "Math.INF > 1000000", true)
+
+	runRuntimeErrStmt(t, "Math.floor(\"1\");")
+	runRuntimeErrStmt(t, "Math.max();")
+	runRuntimeErrStmt(t, "Math.nothing;")
+}
+
+func TestString(t *testing.T) {
+	runExpr(t, "\"abc\".length", 3)
+	runExpr(t, "\"héllo\".length", 5)
+	runExpr(t, "\"héllo\".charAt(1)", "é")
+	runExpr(t, "\"héllo\".indexOf(\"l\")", 2)
+	runExpr(t, "\"héllo\".indexOf(\"x\")", -1)
+	runExpr(t, "\"hello\".contains(\"ell\")", true)
+	runExpr(t, "\"hello\".startsWith(\"he\")", true)
+	runExpr(t, "\"hello\".endsWith(\"he\")", false)
+	runExpr(t, "\"héllo\".slice(1, 3)", "él")
+	runExpr(t, "\"hello\".slice(-3)", "llo")
+	runExpr(t, "\"a,b,c\".split(\",\").length", 3)
+	runExpr(t, "\"a,b,c\".split(\",\")[1]", "b")
+	runExpr(t, "\"  hi \".trim()", "hi")
+	runExpr(t, "\"hi\".upper()", "HI")
+	runExpr(t, "\"HI\".lower()", "hi")
+	runExpr(t, "\"a-b-c\".replace(\"-\", \"+\")", "a+b+c")
+	runExpr(t, "\"ab\".repeat(3)", "ababab")
+	runExpr(t, "\"7\".padStart(3, \"0\")", "007")
+	runExpr(t, "\"7\".padEnd(4, \"ab\")", "7aba")
+	runExpr(t, "\"é\".codePoints()[0]", 233)
+	runExpr(t, "String(12)", "12")
+	runExpr(t, "String(nil)", "nil")
+
+	runStmt(t, "var upper = \"a\".upper; var s = \"b\".lower(); print upper();")
+	runRuntimeErrStmt(t, "\"abc\".nothing;")
+	runRuntimeErrStmt(t, "\"abc\".charAt(\"1\");")
+	runRuntimeErrStmt(t, "\"abc\".slice();")
+	runRuntimeErrStmt(t, "\"ab\".repeat(5000000000000000000);")
+	runRuntimeErrStmt(t, "\"abc\".value;")
+}
+
+func TestArrayMethods(t *testing.T) {
+	runResult(t, "", "[1, 2, 3].map((x) -> x * 2)[2]", 6)
+	runResult(t, "", "[1, 2, 3].map((x, i) -> i)[2]", 2)
+	runResult(t, "", "[1, 2, 3, 4].filter((x) -> x % 2 == 0).length", 2)
+	runResult(t, "", "[1, 2, 3, 4].reduce((acc, x) -> acc + x)", 10)
+	runResult(t, "", "[1, 2, 3, 4].reduce((acc, x) -> acc + x, 10)", 20)
+	runResult(t, "", "[1, 2, 3].find((x) -> x > 1)", 2)
+	runResult(t, "", "[1, 2, 3].find((x) -> x > 5)", nil)
+	runResult(t, "", "[1, 2, 3].findIndex((x) -> x == 3)", 2)
+	runResult(t, "", "[1, 2, 3].some((x) -> x > 2)", true)
+	runResult(t, "", "[1, 2, 3].every((x) -> x > 2)", false)
+	runResult(t, "", "[1, 2, 3].indexOf(2)", 1)
+	runResult(t, "", "[1, 2, 3].includes(4)", false)
+	runResult(t, "", "[1, 2, 3].slice(1)[0]", 2)
+	runResult(t, "", "[1, 2, 3].slice(-1).length", 1)
+	runResult(t, "", "[1].concat([2, 3], [4]).length", 4)
+	runResult(t, "", "[1, 2, 3].reverse()[0]", 3)
+	runResult(t, "", "[1, 2, 3].join(\"-\")", "1-2-3")
+	runResult(t, "", "[3, 1.5, 2].sort()[0]", 1.5)
+	runResult(t, "", "[\"b\", \"c\", \"a\"].sort()[2]", "c")
+	runResult(t, "", "[1, 2, 3].sort((a, b) -> b - a)[0]", 3)
+	runResult(t, "", "Array.range(5).length", 5)
+	runResult(t, "", "Array.range(2, 10, 3)[2]", 8)
+	runResult(t, "", "Array.range(5, 0, -2).length", 3)
+
+	runStmt(t, "var a = [1, 2, 3]; a.pop(); if (a.length != 2) 1();")
+	runStmt(t, "var a = [1, 3]; a.insert(1, 2); if (a[1] != 2) 1(); a.remove(0); if (a[0] != 2) 1();")
+	runStmt(t, "var sum = 0; [1, 2, 3].forEach((x) -> { sum += x; }); if (sum != 6) 1();")
+	// stable sort.
+	runStmt(t, "var a = [[1, \"a\"], [0, \"b\"], [1, \"c\"]].sort((x, y) -> x[0] - y[0]); if (a[2][1] != \"c\") 1();")
+
+	runRuntimeErrStmt(t, "[].pop();")
+	runRuntimeErrStmt(t, "[1].remove(3);")
+	runRuntimeErrStmt(t, "[1, \"a\"].sort();")
+	runRuntimeErrStmt(t, "[].reduce((a, b) -> a + b);")
+	runRuntimeErrStmt(t, "[1].map(1);")
+}
+
+func TestJSON(t *testing.T) {
+	runResult(t, "", "JSON.parse(\"42\")", 42)
+	runResult(t, "", "JSON.parse(\"4.0\")", 4.0)
+	runResult(t, "", "JSON.parse(\"[1, 2.5, null]\")[1]", 2.5)
+	runResult(t, "", "JSON.stringify(JSON.parse(\"[1, 1.0, 1e3]\"))", "[1,1.0,1000.0]")
+	runResult(t, "var o = Object(); o.b = \"x\"; o.a = [1, nil];", "JSON.stringify(o)", "{\"a\":[1,null],\"b\":\"x\"}")
+	runResult(t, "var o = Object(); o.a = [1];", "JSON.stringify(o, 2)", "{\n  \"a\": [\n    1\n  ]\n}")
+	// shared references are not cycles.
+	runResult(t, "var a = [1]; var o = Object(); o.x = a; o.y = a;", "JSON.stringify(o)", "{\"x\":[1],\"y\":[1]}")
+
+	runRuntimeErrStmt(t, "var o = Object(); o.self = o; JSON.stringify(o);")
+	runRuntimeErrStmt(t, "var a = []; a.append(a); JSON.stringify(a);")
+	runRuntimeErrStmt(t, "JSON.stringify(Math.NaN);")
+	runRuntimeErrStmt(t, "JSON.parse(\"[1, 2\");")
+	runRuntimeErrStmt(t, "JSON.parse(\"[1] 2\");")
+}
+
+func TestJSONObject(t *testing.T) {
+	interpreter := NewInterpreter(false)
+	// lox strings can't contain double quotes.
+	interpreter.global.Define("src", `{"a": {"b": [true, 1.5]}, "c": "d"}`)
+
+	if runWith(t, interpreter, "var o = JSON.parse(src); var b = o.a.b[1]; var c = o[\"c\"];") {
+		t.Fatalf("unexpected runtime error: %v", interpreter.LastError())
+	}
+	if b := interpreter.global.values["b"]; b != 1.5 {
+		t.Errorf("expect o.a.b[1] to be 1.5, but got %v", b)
+	}
+	if c := interpreter.global.values["c"]; c != "d" {
+		t.Errorf("expect o[\"c\"] to be d, but got %v", c)
+	}
+}
+
+func TestJSONParseErrorPosition(t *testing.T) {
+	interpreter := NewInterpreter(false)
+	runWith(t, interpreter, "JSON.parse(\"[1,\n  x]\");")
+
+	err := interpreter.LastError()
+	if err == nil || !strings.Contains(err.Error(), "line 2, column 3") {
+		t.Errorf("expect the error to locate line 2, column 3, but got %v", err)
+	}
+}
+
+func TestRegex(t *testing.T) {
+	runResult(t, "", "Regex(\"^a+b$\").test(\"aaab\")", true)
+	runResult(t, "", "Regex(\"^a+b$\").source", "^a+b$")
+	runResult(t, "var m = Regex(\"(\\w+)@(\\w+)\").match(\"mail bob@host now\");", "m[0] + \" \" + m[2]", "bob@host host")
+	runResult(t, "", "Regex(\"(a)|(b)\").match(\"b\")[1]", nil)
+	runResult(t, "", "Regex(\"x\").match(\"abc\")", nil)
+	runResult(t, "var all = Regex(\"(\\d)(\\d)\").matchAll(\"12 34 5\");", "all.length * 10 + all[1][2].length", 21)
+	runResult(t, "", "Regex(\"(\\w+)=(\\w+)\").replace(\"a=1, b=2\", \"$2=$1\")", "1=a, 2=b")
+	runResult(t, "", "Regex(\"\\d+\").replace(\"a1b22\", (m, groups, offset) -> \"<\" + m + \">\")", "a<1>b<22>")
+	runResult(t, "", "Regex(\"(\\d)\").replace(\"a1\", (m, groups) -> groups[0] + groups[0])", "a11")
+	runResult(t, "", "Regex(\"\\s*,\\s*\").split(\"a , b,c\")[1]", "b")
+	runResult(t, "", "Regex(\",\").split(\"a,b,c\", 2)[1]", "b,c")
+	runRuntimeErrStmt(t, "Regex(\"(\");")
+	runRuntimeErrStmt(t, "Regex(\"a\").replace(\"a\", (m) -> 1);")
+	// the compiled pattern is out of reach of scripts.
+	runResult(t, "var re = Regex(\"a\"); re.regexp = 1;", "re.test(\"a\")", true)
+	runResult(t, "", "Reflect.fields(Regex(\"a\")).length", 0)
+}
+
+func TestOperatorOverloading(t *testing.T) {
+	vector := `
+	class Vector {
+		init(x, y) { this.x = x; this.y = y; }
+		__add__(other) { return Vector(this.x + other.x, this.y + other.y); }
+		__sub__(other) { return Vector(this.x - other.x, this.y - other.y); }
+		__mul__(k) { return Vector(this.x * k, this.y * k); }
+		__rmul__(k) { return this * k; }
+		__div__(k) { return Vector(this.x / k, this.y / k); }
+		__mod__(k) { return Vector(this.x % k, this.y % k); }
+		__neg__() { return Vector(-this.x, -this.y); }
+		__eq__(other) { return this.x == other.x and this.y == other.y; }
+		__lt__(other) { return this.x * this.x + this.y * this.y < other.x * other.x + other.y * other.y; }
+		__getitem__(i) { if (i == 0) return this.x; return this.y; }
+		__setitem__(i, v) { if (i == 0) this.x = v; else this.y = v; }
+	}
+	class Vector3 < Vector {}
+	var a = Vector(1, 2);
+	var b = Vector3(3, 4);
+	`
+	runResult(t, vector, "(a + b).y", 6)
+	runResult(t, vector, "(b - a).x", 2)
+	runResult(t, vector, "(a * 3).y", 6)
+	runResult(t, vector, "(3 * a).x", 3)
+	runResult(t, vector, "(b / 2).y", 2)
+	runResult(t, vector, "(b % 2).x", 1)
+	runResult(t, vector, "(-a).x", -1)
+	runResult(t, vector, "a == Vector(1, 2)", true)
+	runResult(t, vector, "a != b", true)
+	runResult(t, vector, "a < b", true)
+	runResult(t, vector, "a > b", false)
+	runResult(t, vector, "b >= a", true)
+	runResult(t, vector, "a <= a", true)
+
+	// comparisons with a primitive on either side.
+	number := `
+	class N {
+		init(x) { this.x = x; }
+		__lt__(other) { return this.x < other; }
+		__eq__(other) { return this.x == other; }
+	}
+	class Big { __gt__(other) { return true; } __le__(other) { return false; } }
+	`
+	for src, expected := range map[string]bool{
+		"N(1) < 5": true, "N(1) > 0": true, "N(1) > 1": false, "N(1) <= 3": true, "N(1) <= 1": true, "N(1) >= 2": false,
+		"5 > N(1)": true, "0 < N(1)": true, "1 < N(1)": false, "3 >= N(1)": true, "1 >= N(1)": true, "2 <= N(1)": false,
+		"Big() > 1": true, "1 < Big()": true, "Big() <= 1": false, "1 >= Big()": false,
+	} {
+		runResult(t, number, src, expected)
+	}
+	runRuntimeErrStmt(t, "class Big { __gt__(other) { return true; } } Big() >= 1;")
+	runResult(t, vector, "b[1]", 4)
+	runResult(t, vector+"b[0] = 7;", "b.x", 7)
+	runResult(t, vector+"var c = a; c += b;", "c.x", 4)
+	runRuntimeErrStmt(t, "class A {} A() + 1;")
+}
+
+func TestArraySubscriptAssign(t *testing.T) {
+	runResult(t, "var a = [1, 2, 3]; for (var i = 0; i < 3; i = i + 1) { a[i] = a[i] * 2; }", "a[2]", 6)
+	runResult(t, "var a = []; a[\"foo\"] = 1;", "a[\"foo\"]", 1)
+	runRuntimeErrStmt(t, "var a = [1]; a[1] = 2;")
+	runRuntimeErrStmt(t, "var a = [1]; a[-1];")
+	// the elements are out of reach of scripts.
+	runResult(t, "var a = [1, 2]; a.list = 5;", "a.length", 2)
+	runRuntimeErrStmt(t, "[1, 2, 3][\"list\"];")
+}
+
+func TestFormat(t *testing.T) {
+	point := `
+	class Point {
+		init(x, y) { this.y = y; this.x = x; }
+	}
+	class Named < Point {
+		toString() { return "(" + this.x + ", " + this.y + ")"; }
+	}
+	`
+	runResult(t, point, "String(Point(1, \"a\"))", "Point { x: 1, y: \"a\" }")
+	runResult(t, point, "\"p = \" + Named(1, 2)", "p = (1, 2)")
+	runResult(t, point, "String([Named(1, 2), [nil, true], Point(nil, [])])", "[(1, 2), [nil, true], Point { x: nil, y: [] }]")
+	runResult(t, point, "1.5 + \"\" + 2", "1.52")
+	runResult(t, "var a = [1]; a.append(a);", "String(a)", "[1, <cycle>]")
+	runResult(t, point+"var p = Point(1, 2); p.y = p;", "String(p)", "Point { x: 1, y: <cycle> }")
+	runResult(t, point+"var p = Point(1, 2); var shared = [p, p];", "String(shared)", "[Point { x: 1, y: 2 }, Point { x: 1, y: 2 }]")
+	runResult(t, "class A { toString() { return \"A\" + this; } }", "String(A())", "A<cycle>")
+	runRuntimeErrStmt(t, "class A { toString() { return 1; } } print A();")
+}
+
+func TestSuperProperties(t *testing.T) {
+	classes := `
+	class Animal {
+		init(name) { this._name = name; }
+		get name { return this._name; }
+		set name(value) { this._name = value; }
+		static create(name) { return Animal(name); }
+		static kind() { return "animal"; }
+	}
+	class Dog < Animal {
+		get name { return "dog " + super.name; }
+		set name(value) { super.name = value + "!"; }
+		static kind() { return "dog, an " + super.kind(); }
+	}
+	var dog = Dog("rex");
+	`
+	runResult(t, classes, "dog.name", "dog rex")
+	runResult(t, classes+"dog.name = \"max\";", "dog.name", "dog max!")
+	runResult(t, classes, "Dog.create(\"rex\").name", "rex")
+	runResult(t, classes, "Dog.kind()", "dog, an animal")
+	runResult(t, classes+"fun outer() { var local = 1; class A < Animal { static f() { return local + super.kind().length; } } return A.f(); }", "outer()", 7)
+	runRuntimeErrStmt(t, "class A { get x { return 1; } } class B < A { f() { super.x = 2; } } B().f();")
+	// compound assignments read the property of the superclass first.
+	runResult(t, classes+"class Puppy < Animal { grow() { super.name += \" jr\"; } } var puppy = Puppy(\"rex\"); puppy.grow();", "puppy.name", "rex jr")
+	runResult(t, "class A { static var n = 2; } class B < A { static double() { super.n *= 2; } } B.double();", "A.n", 4)
+	runRuntimeErrStmt(t, "class A {} class B < A { static f() { return super.g(); } } B.f();")
+
+	runResErrStmt(t, "class A { static f() { return this; } }")
+	runResErrStmt(t, "class A { f() { super.x = 1; } }")
+	runResErrStmt(t, "class A {} class B < A { f() { class C { g() { return super.f(); } } } }")
+}
+
+func TestStaticFields(t *testing.T) {
+	classes := `
+	class Counter {
+		static var count = 0, step = 1;
+		static var log;
+		static {
+			Counter.log = [];
+			Counter.log.append("init");
+		}
+		static get double { return Counter.count * 2; }
+		static set reset(value) { Counter.count = value; }
+		init() { Counter.count = Counter.count + Counter.step; }
+	}
+	class Sub < Counter {
+		static var step = 10;
+		static { Sub.created = super.double; super.step = 2; }
+	}
+	Counter(); Counter();
+	`
+	runResult(t, classes, "Counter.count", 4)
+	runResult(t, classes, "Counter.double", 8)
+	runResult(t, classes, "Counter.log.length", 1)
+	runResult(t, classes+"Counter.reset = 5;", "Counter.count", 5)
+	// fields are shared with subclasses, unless they redeclare them.
+	runResult(t, classes+"Sub();", "Sub.count", 6)
+	runResult(t, classes+"Sub.count = 7;", "Counter.count", 7)
+	runResult(t, classes, "Sub.step", 10)
+	runResult(t, classes, "Counter.step", 2)
+	runResult(t, classes, "Sub.created", 0)
+	runResult(t, classes, "Sub.double", 8)
+	runResult(t, classes+"Sub.extra = 1;", "Sub.extra", 1)
+	// compound assignments read the member first.
+	runResult(t, "class A { static var count = 10; init() { A.count += 1; } } A(); A();", "A.count", 12)
+	runResult(t, "class A { static var n = 3; } A.n *= 2; A.n -= 1;", "A.n", 5)
+	runResult(t, "class P { init() { this.x = 1; } } var p = P(); p.x += 2; p[\"x\"] *= 3;", "p.x", 9)
+	runResult(t, "var a = [1, 2]; a[1] += 5;", "a[1]", 7)
+	runRuntimeErrStmt(t, "class A {} A.missing;")
+	runRuntimeErrStmt(t, "Math.PI = 3;")
+
+	// the members of a subclass shadow the ones of its superclasses.
+	shadowed := `
+	class A {
+		static var x = 1, y = 1, log = [];
+		static set z(value) { A.log.append(value); }
+	}
+	class B < A {
+		static get x { return 2; }
+		static var z = 0;
+		static y() { return 3; }
+	}
+	`
+	runResult(t, shadowed, "B.x", 2)
+	runResult(t, shadowed, "B.y()", 3)
+	runResult(t, shadowed+"B.z = 5;", "B.z * 10 + A.log.length", 50)
+	runResult(t, shadowed+"A.z = 5;", "B.z * 10 + A.log.length", 1)
+	runResult(t, "class A { static get x { return 1; } static set x(v) {} }", "A.x", 1)
+
+	runResErrStmt(t, "class A { static var x = this; }")
+	runResErrStmt(t, "class A { static { return; } }")
+	runResErrStmt(t, "class A { static var x, x; }")
+	runResErrStmt(t, "class A { static get x { return this; } }")
+	runResErrStmt(t, "class A { static var x = 1; static x() {} }")
+	runResErrStmt(t, "class A { static x() {} static get x { return 1; } }")
+	runResErrStmt(t, "class A { static get x { return 1; } static set x(v) {} static get x { return 2; } }")
+}
+
+func TestPrivateMembers(t *testing.T) {
+	account := `
+	class Account {
+		init(balance) { this.#balance = balance; this.owner = "bob"; }
+		#check(amount) { return amount <= this.#balance; }
+		get #doubled { return this.#balance * 2; }
+		withdraw(amount) {
+			if (this.#check(amount)) this.#balance = this.#balance - amount;
+			return this.#balance;
+		}
+		doubled() { return this.#doubled; }
+		later() { return () -> this.#balance; }
+	}
+	class Savings < Account {
+		init(balance) { super.init(balance); this.#balance = 0; }
+		mine() { return this.#balance; }
+	}
+	var account = Account(10);
+	`
+	runResult(t, account, "account.withdraw(3)", 7)
+	runResult(t, account, "account.withdraw(30)", 10)
+	runResult(t, account, "account.doubled()", 20)
+	runResult(t, account, "account.later()()", 10)
+	runResult(t, account, "String(account)", "Account { owner: \"bob\" }")
+	// each class has its own private members.
+	runResult(t, account+"var savings = Savings(5);", "savings.withdraw(1) * 10 + savings.mine()", 40)
+
+	// the interpreter enforces the access even if the resolver is skipped.
+	interpreter := NewInterpreter(false)
+	if runWith(t, interpreter, account) {
+		t.Fatalf("unexpected runtime error: %v", interpreter.LastError())
+	}
+	for _, src := range []string{"account.#balance;", "account.#balance = 1;", "account.#check(1);", "account[\"#balance\"];"} {
+		tokens, _ := NewScanner(src).ScanTokens()
+		stmts, _ := NewParser(tokens).Parse()
+		if !interpreter.Interprete(stmts) {
+			t.Errorf("expect %v to be a runtime error.", src)
+		}
+	}
+	runRuntimeErrStmt(t, "var key = \"#x\"; class A {} A()[key] = 1;")
+
+	runResErrStmt(t, account+"account.#balance;")
+	runResErrStmt(t, "class A { f(other) { return other.#x; } init() { this.#x = 1; } }")
+	runResErrStmt(t, "class A { f() { return this.#missing; } }")
+	runResErrStmt(t, "class A { f() { return this[\"#x\"]; } }")
+	runResErrStmt(t, "var #x = 1;")
+	runResErrStmt(t, "class A { static #f() {} }")
+	runResErrStmt(t, "class A { static var #count = 0; }")
+	runResErrStmt(t, "class A { init() { this.#x = 1; } } class B < A { f() { return this.#x; } }")
+}
+
+func TestTraits(t *testing.T) {
+	shapes := `
+	trait Comparable {
+		compare(other) { return this.size - other.size; }
+		less(other) { return this.compare(other) < 0; }
+	}
+	trait Printable {
+		describe() { return this.name() + " of size " + String(this.size); }
+		get label { return "<" + this.name() + ">"; }
+	}
+	class Shape {
+		init(size) { this.size = size; }
+		name() { return "shape"; }
+		describe() { return "a shape"; }
+	}
+	class Square < Shape with Comparable, Printable {
+		name() { return "square"; }
+		less(other) { return !super.less(other); }
+	}
+	trait Named {
+		name() { return "named " + super.name(); }
+	}
+	class Circle < Shape with Named {}
+	var a = Square(1);
+	var b = Square(2);
+	`
+	runResult(t, shapes, "a.compare(b)", -1)
+	// the trait overrides the superclass.
+	runResult(t, shapes, "a.describe()", "square of size 1")
+	runResult(t, shapes, "a.label", "<square>")
+	// the class overrides the trait, & reaches it through super.
+	runResult(t, shapes, "a.less(b)", false)
+	// super in a trait refers to the superclass of the class composing it.
+	runResult(t, shapes, "Circle(3).name()", "named shape")
+	runResult(t, shapes, "String(Comparable)", "<trait Comparable>")
+
+	conflict := `
+	trait A { f() { return 1; } }
+	trait B { f() { return 2; } }
+	`
+	runResErrStmt(t, conflict+"class C with A, B {}")
+	runResErrStmt(t, conflict+"class C with A, A {}")
+	runResult(t, conflict+"class C with A, B { f() { return 3; } }", "C().f()", 3)
+	// the resolver doesn't know the traits behind variables.
+	runRuntimeErrStmt(t, conflict+"var D = B; class C with A, D {}")
+	runRuntimeErrStmt(t, "class A {} class B with A {}")
+	runRuntimeErrStmt(t, "trait A { f() { return super.f(); } } class B with A {} B().f();")
+
+	runResErrStmt(t, "trait A { init() {} }")
+	runResErrStmt(t, "trait A { #f() {} }")
+	runResErrStmt(t, "trait A { f() { return this.#x; } }")
+	parseErrStmt(t, "trait A { static f() {} }")
+}
+
+func TestInterfaces(t *testing.T) {
+	plugins := `
+	interface Plugin {
+		run(input);
+		get name;
+	}
+	trait Named {
+		get name { return "named"; }
+	}
+	class Base {
+		run(input) { return input * 2; }
+	}
+	class Doubler < Base with Named implements Plugin {}
+	class Other {}
+	`
+	runResult(t, plugins, "Doubler().run(2)", 4)
+	runResult(t, plugins, "implements(Doubler(), Plugin)", true)
+	runResult(t, plugins, "implements(Doubler, Plugin)", true)
+	runResult(t, plugins, "implements(Other(), Plugin)", false)
+	runResult(t, plugins, "implements(1, Plugin)", false)
+	runResult(t, plugins, "String(Plugin)", "<interface Plugin>")
+
+	runResErrStmt(t, plugins+"class A implements Plugin { get name { return 1; } }")
+	runResErrStmt(t, plugins+"class A implements Plugin { get name { return 1; } run() {} }")
+	runResErrStmt(t, plugins+"class A implements Plugin { run(input) {} }")
+	runResErrStmt(t, "interface A { f(); f(a); }")
+	// the resolver doesn't know the superclass behind a variable, the interpreter checks it.
+	runRuntimeErrStmt(t, plugins+"var B = Other; class A < B implements Plugin { get name { return 1; } }")
+	runRuntimeErrStmt(t, plugins+"class A implements Other {}")
+	runRuntimeErrStmt(t, "implements(1, 2);")
+	parseErrStmt(t, "interface A { f() {} }")
+}
+
+func TestTypes(t *testing.T) {
+	zoo := `
+	trait Walks { walk() { return "walking"; } }
+	interface Speaks { speak(); }
+	class Animal {}
+	class Dog < Animal with Walks implements Speaks { speak() { return "woof"; } }
+	class Puppy < Dog {}
+	var dog = Dog();
+	`
+	runResult(t, zoo, "dog is Dog", true)
+	runResult(t, zoo, "dog is Animal", true)
+	runResult(t, zoo, "dog is Puppy", false)
+	runResult(t, zoo, "Puppy() is Walks", true)
+	runResult(t, zoo, "Puppy() is Speaks", true)
+	runResult(t, zoo, "Animal() is Speaks", false)
+	runResult(t, zoo, "1 is Animal", false)
+	runResult(t, zoo, "[1] is Array", true)
+	runResult(t, zoo, "classOf(dog) == Dog", true)
+	runResult(t, zoo, "classOf([]) == Array", true)
+	runResult(t, zoo, "classOf(1)", nil)
+	runResult(t, zoo, "classOf(\"abc\") == String", true)
+	runResult(t, zoo, "\"abc\" is String", true)
+	runResult(t, zoo, "\"abc\" is Animal", false)
+	runRuntimeErrStmt(t, "1 is 2;")
+
+	for src, expected := range map[string]string{
+		"1":             "number",
+		"1.5":           "number",
+		"\"a\"":         "string",
+		"true":          "bool",
+		"nil":           "nil",
+		"clock":         "function",
+		"() -> 1":       "function",
+		"Array":         "class",
+		"[]":            "instance",
+		"Regex(\"a\")":  "instance",
+		"Array.isArray": "function",
+	} {
+		runResult(t, "", "type("+src+")", expected)
+	}
+
+	runRuntimeErrStmt(t, "type(1, true);")
+
+	runResult(t, "", "Array.isArray([1, 2])", true)
+	runResult(t, "", "Array.isArray(Array(1, 2))", true)
+	runResult(t, "", "Array.isArray(Regex(\"a\"))", false)
+}
+
+func TestReflect(t *testing.T) {
+	src := `
+	trait Greets { greet() { return "hi"; } }
+	class Base { static create() { return Point(0, 0); } }
+	class Point < Base with Greets {
+		init(x, y) { this.x = x; this.y = y; this.#secret = 1; }
+		norm() { return this.x + this.y; }
+		#hidden() {}
+		get sum { return this.x + this.y; }
+		set sum(value) { this.x = value; }
+		static origin() { return Point(0, 0); }
+	}
+	var p = Point(1, 2);
+	fun add(a, b) { return a + b; }
+	`
+	runResult(t, src, "String(Reflect.fields(p))", "[\"x\", \"y\"]")
+	runResult(t, src, "Reflect.hasField(p, \"x\")", true)
+	runResult(t, src, "Reflect.hasField(p, \"norm\")", false)
+	runResult(t, src, "Reflect.getField(p, \"y\")", 2)
+	runResult(t, src, "Reflect.getField(p, \"z\")", nil)
+	runResult(t, src, "Reflect.setField(p, \"z\", 3) + p.z", 6)
+	runResult(t, src, "Reflect.deleteField(p, \"x\") and !Reflect.hasField(p, \"x\")", true)
+	runResult(t, src, "Reflect.deleteField(p, \"missing\")", false)
+	runResult(t, src, "String(Reflect.methods(Point))", "[\"init\", \"norm\"]")
+	runResult(t, src, "String(Reflect.getters(Point))", "[\"sum\"]")
+	runResult(t, src, "String(Reflect.setters(Point))", "[\"sum\"]")
+	runResult(t, src, "String(Reflect.statics(Point))", "[\"origin\"]")
+	runResult(t, "class C { static var n = 1; static get g { return 1; } static set g(v) {} static set s(v) {} static f() {} }",
+		"String(Reflect.statics(C))", "[\"f\", \"g\", \"n\", \"s\"]")
+	runResult(t, "", "Reflect.statics(Math).includes(\"PI\")", true)
+	runResult(t, src, "String(Reflect.methods(Greets))", "[\"greet\"]")
+	runResult(t, src, "Reflect.superclass(Point) == Base", true)
+	runResult(t, src, "Reflect.superclass(Base)", nil)
+	runResult(t, src, "Reflect.traits(Point)[0] == Greets", true)
+	runResult(t, src, "Reflect.name(add)", "add")
+	runResult(t, src, "Reflect.name(() -> 1)", nil)
+	runResult(t, src, "Reflect.name(Point)", "Point")
+	runResult(t, src, "Reflect.name(p.norm)", "norm")
+	runResult(t, src, "Reflect.name(clock)", "clock")
+	runResult(t, src, "Reflect.name([].append)", "append")
+	runResult(t, src, "Reflect.arity(add)", 2)
+	runResult(t, src, "Reflect.arity(Point)", 2)
+	runResult(t, src, "Reflect.arity(Math.max)", -1)
+	runResult(t, src, "String(Reflect.params(add))", "[\"a\", \"b\"]")
+	runResult(t, src, "String(Reflect.params(clock))", "[]")
+	runResult(t, src, "String(Reflect.params(Math.max))", "[\"...args\"]")
+
+	runRuntimeErrStmt(t, "Reflect.fields(1);")
+	runRuntimeErrStmt(t, "class A {} Reflect.getField(A(), \"#x\");")
+	runRuntimeErrStmt(t, "Reflect.methods(1);")
+	runRuntimeErrStmt(t, "Reflect.params(1);")
+}
+
+func TestEnums(t *testing.T) {
+	colors := `
+	enum Color {
+		Red, Green, Blue;
+		warm() { return this == Color.Red; }
+		get lower { return this.name + "!"; }
+	}
+	`
+	runResult(t, colors, "Color.Red.name", "Red")
+	runResult(t, colors, "Color.Blue.ordinal", 2)
+	runResult(t, colors, "Color.Red == Color.Red", true)
+	runResult(t, colors, "Color.Red == Color.Green", false)
+	runResult(t, colors, "Color.Red.warm() and !Color.Blue.warm()", true)
+	runResult(t, colors, "Color.Green.lower", "Green!")
+	runResult(t, colors, "String(Color.values())", "[Color.Red, Color.Green, Color.Blue]")
+	runResult(t, colors, "Color.from(\"Blue\") == Color.Blue", true)
+	runResult(t, colors, "Color.from(\"Pink\")", nil)
+	runResult(t, colors, "Color.Red is Color", true)
+	runResult(t, "enum Answer { Yes, No, }", "String(Answer.No)", "Answer.No")
+
+	runRuntimeErrStmt(t, colors+"Color();")
+	runRuntimeErrStmt(t, colors+"Color.Red = 1;")
+	runRuntimeErrStmt(t, colors+"Color.Red.name = \"z\";")
+	runRuntimeErrStmt(t, colors+"Color.Red.extra = 1;")
+	runRuntimeErrStmt(t, colors+"class Pink < Color {}")
+	runResErrStmt(t, "enum A { X, X }")
+	runResErrStmt(t, "enum A { X; get name { return 1; } }")
+	runResErrStmt(t, "enum A { X; init() {} }")
+	runResErrStmt(t, "enum A { X; set value(v) {} }")
+	parseErrStmt(t, "enum A { }")
+}
+
+func TestConst(t *testing.T) {
+	runResult(t, "const a = 1, b = a + 1;", "a + b", 3)
+	runResult(t, "const a = 1; fun f() { var a = 2; a = 3; return a; }", "f()", 3)
+	runResult(t, "var x = 1; x %= 2;", "x", 1)
+
+	runResErrStmt(t, "{ const a = 1; a = 2; }")
+	runResErrStmt(t, "{ const a = 1; a += 2; }")
+	runResErrStmt(t, "fun f() { const a = 1; return () -> a = 2; }")
+	runResErrStmt(t, "{ const a = 1; var a = 2; }")
+	// globals are checked at runtime, & by the resolver once declared.
+	runRuntimeErrStmt(t, "const a = 1; a = 2;")
+	runResErrStmt(t, "const a = 1; a = 2;")
+	runResErrStmt(t, "const a = 1; fun f() { a += 1; }")
+	runResult(t, "const a = 1; fun f() { var a = 2; a = 3; return a; }", "f()", 3)
+	runRuntimeErrStmt(t, "const a = 1; fun f() { a *= 2; } f();")
+	parseErrStmt(t, "const a;")
+}
+
+func TestFreeze(t *testing.T) {
+	src := `
+	class Point {
+		init(x) { this.x = x; this.#y = 0; }
+		move() { this.#y = 1; }
+	}
+	var p = freeze(Point(1));
+	var a = freeze([3, 1, 2]);
+	`
+	runResult(t, src, "p.x + a[0]", 4)
+	runResult(t, src, "isFrozen(p) and isFrozen(a) and !isFrozen([])", true)
+	runResult(t, src, "String(a.map((x) -> x * 2))", "[6, 2, 4]")
+
+	for _, mutation := range []string{
+		"p.x = 2;", "p[\"x\"] = 2;", "p.move();", "Reflect.setField(p, \"x\", 2);", "Reflect.deleteField(p, \"x\");",
+		"a[0] = 1;", "a.append(4);", "a.pop();", "a.insert(0, 1);", "a.remove(0);", "a.reverse();", "a.sort();",
+		"Random.shuffle(a);", "freeze(1);",
+	} {
+		runRuntimeErrStmt(t, src+mutation)
+	}
+}
+
+func TestConditionalOperators(t *testing.T) {
+	runResult(t, "", "true ? 1 : 2", 1)
+	runResult(t, "", "nil ? 1 : false ? 2 : 3", 3)
+	runResult(t, "var x = 1;", "(x > 0 ? [x] : [0])[0]", 1)
+	runResult(t, "", "nil ?? 1", 1)
+	runResult(t, "", "false ?? 1", false)
+	runResult(t, "", "nil ?? nil ?? 2", 2)
+	runResult(t, "", "nil or 1 ?? 2", 1)
+
+	objects := `
+	var calls = 0;
+	fun count() { calls += 1; return calls; }
+	class Node {
+		init(next) { this.next = next; }
+		value() { return 42; }
+	}
+	var list = Node(Node(nil));
+	var none = nil;
+	var arr = [1, 2];
+	`
+	runResult(t, objects, "list?.next.value()", 42)
+	runResult(t, objects, "list.next.next?.next.value()", nil)
+	runResult(t, objects, "none?.value()", nil)
+	runResult(t, objects, "none?.next.next", nil)
+	runResult(t, objects, "list.next?.value?.()", 42)
+	runResult(t, objects, "none?.f(count()) ?? calls", 0)
+	runResult(t, objects, "arr?[1]", 2)
+	runResult(t, objects, "none?[count()] ?? calls", 0)
+	runResult(t, objects, "true ? count() : count() + 10", 1)
+	runResult(t, objects, "false ? count() + 10 : calls", 0)
+	runResult(t, objects, "1 ?? count()", 1)
+	runResult(t, objects, "(none?.next)?.next", nil)
+
+	runRuntimeErrStmt(t, "var a; a?.b.c(); nil.x;")
+	runRuntimeErrStmt(t, "var a = nil; (a?.b).c;")
+	parseErrStmt(t, "var a; a?.b = 1;")
+	parseErrStmt(t, "true ? 1;")
+}
+
+func TestMatch(t *testing.T) {
+	classes := `
+	class Point { init(x, y) { this.x = x; this.y = y; } }
+	class Point3 < Point { init(x, y, z) { super.init(x, y); this.z = z; } }
+	enum Color { Red, Green }
+	fun describe(value) {
+		return match (value) {
+			case 1, 2 -> "small"
+			case -1 -> "negative"
+			case 3..10 -> "medium"
+			case "x" -> "x"
+			case nil -> "nil"
+			case Color.Red -> "red"
+			case Point3(_, _, z) -> "point3 " + z
+			case Point(0, y) -> "on y " + y
+			case Point(x, y) if x == y -> "diagonal"
+			case Point() -> "point"
+			case [] -> "empty"
+			case [first, [inner]] -> "nested " + inner
+			case [first, ...rest] -> "first " + first + " of " + (rest.length + 1)
+			case n if n is Color -> "color"
+			case _ -> "other"
+		};
+	}
+	`
+	runResult(t, classes, "describe(2)", "small")
+	runResult(t, classes, "describe(-1)", "negative")
+	runResult(t, classes, "describe(4.5)", "medium")
+	runResult(t, classes, "describe(10)", "medium")
+	runResult(t, classes, "describe(11)", "other")
+	runResult(t, classes, "describe(\"x\")", "x")
+	runResult(t, classes, "describe(nil)", "nil")
+	runResult(t, classes, "describe(Color.Red)", "red")
+	runResult(t, classes, "describe(Color.Green)", "color")
+	runResult(t, classes, "describe(Point3(1, 2, 3))", "point3 3")
+	runResult(t, classes, "describe(Point(0, 5))", "on y 5")
+	runResult(t, classes, "describe(Point(4, 4))", "diagonal")
+	runResult(t, classes, "describe(Point(1, 2))", "point")
+	runResult(t, classes, "describe([])", "empty")
+	runResult(t, classes, "describe([1, [2]])", "nested 2")
+	runResult(t, classes, "describe([1, 2, 3])", "first 1 of 3")
+	runResult(t, classes, "describe(true)", "other")
+
+	statement := `
+	var log = "";
+	for (var i = 0; i < 4; i += 1) {
+		match (i) {
+			case 0 -> log += "zero ";
+			case n if n % 2 == 1 -> { var odd = n; log += "odd" + odd + " "; }
+			case _ -> break;
+		}
+	}
+	`
+	runResult(t, statement, "log", "zero odd1 ")
+	runResult(t, "var a = 1;", "match (a) { case 2 -> 1, case a -> a + 1 }", 2)
+	runResult(t, "", "Regex(\"a\").match(\"a\")[0]", "a")
+
+	unmatched := NewInterpreter(false)
+	if !runWith(t, unmatched, "match (1) { case 2 -> 2 }; print 1 + match (1) { case 2 -> 2 };") {
+		t.Error("expect runtime error.")
+	}
+	noFields := NewInterpreter(false)
+	if !runWith(t, noFields, "class A {} match (A()) { case A(x) -> print x; }") {
+		t.Error("expect runtime error.")
+	}
+
+	runResErrStmt(t, "match (1) { case 1, x -> print x; }")
+	runResErrStmt(t, "match (1) { case [x, x] -> print x; }")
+	runResErrStmt(t, "match (1) { case _ -> break; }")
+	parseErrStmt(t, "match (1) { case \"a\"..\"b\" -> 1; }")
+	parseErrStmt(t, "match (1) { case [...a, b] -> 1; }")
+	parseErrStmt(t, "match 1 { case 1 -> 1; }")
+
+	warnings := map[string]int{
+		"match (1) { case 1 -> 1; case 2 -> 2; }":                        0,
+		"match (1) { case _ -> 1; case 2 -> 2; }":                        1,
+		"match (1) { case x -> 1; case 2 -> 2; case 3 -> 3; }":           2,
+		"match (1) { case x if x > 0 -> 1; case 2 -> 2; }":               0,
+		"match (1) { case 1, 2 -> 1; case 2 -> 2; case 1, 3 -> 3; }":     1,
+		"match (1) { case [x] -> 1; case [y] -> 2; case Point() -> 3; }": 0,
+	}
+	for src, count := range warnings {
+		tokens, _ := NewScanner(src).ScanTokens()
+		stmts, _ := NewParser(tokens).Parse()
+		resolver := NewResolver(NewInterpreter(false))
+		resolver.Resolve(stmts)
+		if len(resolver.warnings) != count {
+			t.Errorf("expect %v warnings for %v, but got %v", count, src, len(resolver.warnings))
+		}
+	}
+}
+
+func TestDestructuring(t *testing.T) {
+	runResult(t, "var [a, b, ...rest] = [1, 2, 3, 4];", "a + b + rest.length", 5)
+	runResult(t, "var [a, [b, c]] = [1, [2, 3]];", "a + b + c", 6)
+	runResult(t, "var [a, _, b = 10, c = a] = [1, 2];", "[a, b, c].join(\" \")", "1 10 1")
+	runResult(t, "var [a, ...rest] = [1];", "rest.length", 0)
+	runResult(t, "var a = 1, b = 2; [a, b] = [b, a];", "a * 10 + b", 21)
+	runResult(t, "var a; var b; [a, [_, b = 5]] = [1, [2]];", "a + b", 6)
+	runResult(t, "var a; { var b; [a, b] = [1, 2]; a += b; }", "a", 3)
+
+	objects := `
+	class Point {
+		init(x, y) { this.x = x; this.y = y; }
+		get norm { return this.x + this.y; }
+	}
+	var point = Point(1, 2);
+	`
+	runResult(t, objects+"var {x, y} = point;", "x + y", 3)
+	runResult(t, objects+"var {x: left, norm, z = 7} = point;", "left + norm + z", 11)
+	runResult(t, "var o = Object(); o.a = 1; o.b = [2]; var {a, b: [c]} = o;", "a + c", 3)
+	runResult(t, objects+"fun sum({x, y}, [a, b] ) { return x + y + a + b; }", "sum(point, [3, 4])", 10)
+	runResult(t, "class A { add([a, b]) { return a + b; } }", "A().add([1, 2])", 3)
+	runResult(t, "fun f([a, b], c) {}", "Reflect.params(f).join(\" \")", "[a, b] c")
+	runResult(t, objects, "match (point) { case {x: 1, y} -> y, case _ -> 0 }", 2)
+	runResult(t, objects, "match (point) { case {z} -> 1, case {z = 3} -> z }", 3)
+	runResult(t, "", "match ([1]) { case [a, b = 2] -> a + b }", 3)
+	runResult(t, "for (var [i, j] = [0, 0]; i < 3; i += 1) { j += i; }", "1", 1)
+
+	runRuntimeErrStmt(t, "var [a, b] = [1];")
+	runRuntimeErrStmt(t, "var [a] = [1, 2];")
+	runRuntimeErrStmt(t, "var {x} = 1;")
+	runRuntimeErrStmt(t, "class A {} var {x} = A();")
+	runRuntimeErrStmt(t, "fun f([a]) {} f(1);")
+	runResErrStmt(t, "var z; const j = 2; [z, j] = [3, 4];")
+
+	// a failed destructuring assigns none of the variables.
+	for _, src := range []string{"var z = 1, y = 1; [z, [y]] = [3, 4];", "var z = 1; const j = 2; [z, j] = [3, 4];", "var z = 1; [z, missing] = [3, 4];"} {
+		tokens, _ := NewScanner(src).ScanTokens()
+		stmts, _ := NewParser(tokens).Parse()
+		interpreter := NewInterpreter(false)
+		if !interpreter.Interprete(stmts) {
+			t.Errorf("expect %v to be a runtime error.", src)
+		}
+		if z := interpreter.global.values["z"]; z != 1 {
+			t.Errorf("expect %v to leave z unchanged, but got %v", src, z)
+		}
+	}
+
+	runResErrStmt(t, "{ const [a, b] = [1, 2]; a = 3; }")
+	runResErrStmt(t, "{ const [a, b] = [1, 2]; [a, b] = [b, a]; }")
+	runResErrStmt(t, "{ var [a, a] = [1, 2]; }")
+	runResErrStmt(t, "fun f([a], a) {}")
+
+	parseErrStmt(t, "var [a, b];")
+	parseErrStmt(t, "var [a, 1] = [1, 1];")
+	parseErrStmt(t, "var [a] = [1], b = 2;")
+	parseErrStmt(t, "var a; [a.b] = [1];")
+	parseErrStmt(t, "class A { static var [a] = [1]; }")
+
+	runRuntimeErrStmt(t, "const [a, {b}] = [1, Object()]; a = 2;")
+	runResErrStmt(t, "const [a, {b}] = [1, Object()]; a = 2;")
+}
+
+func TestParametersAndSpread(t *testing.T) {
+	functions := `
+	fun add(a, b = 10, c = a + b) { return a + b + c; }
+	fun count(first, ...rest) { return rest.length; }
+	fun all(...items) { return items; }
+	class Range {
+		init(low, high = low + 1) { this.low = low; this.high = high; }
+		span(...steps) { return this.high - this.low + steps.length; }
+	}
+	var numbers = [1, 2, 3];
+	`
+	runResult(t, functions, "add(1)", 22)
+	runResult(t, functions, "add(1, 2)", 6)
+	runResult(t, functions, "add(1, 2, 3)", 6)
+	runResult(t, functions, "count(1)", 0)
+	runResult(t, functions, "count(1, 2, 3)", 2)
+	runResult(t, functions, "all().length", 0)
+	runResult(t, functions, "Range(1).high", 2)
+	runResult(t, functions, "Range(1, 5).span(0, 0)", 6)
+	runResult(t, functions, "add(...numbers)", 6)
+	runResult(t, functions, "add(...[1], 2)", 6)
+	runResult(t, functions, "count(...numbers, ...numbers)", 5)
+	runResult(t, functions, "[0, ...numbers, 4, ...[]].join(\",\")", "0,1,2,3,4")
+	runResult(t, functions, "Math.max(...numbers)", 3)
+	runResult(t, functions, "[1, 2, 3].map((x) -> x).length", 3)
+	// lambdas take the same parameters as functions.
+	runResult(t, "var f = (a, b = 1) -> a + b;", "f(1) * 10 + f(1, 2)", 23)
+	runResult(t, "var f = (...rest) -> rest.length;", "f(1, 2, 3)", 3)
+	runResult(t, "var f = ([a, b], {c}) -> a + b + c; var o = Object(); o.c = 3;", "f([1, 2], o)", 6)
+	runResult(t, "var a = 1; var b = 2;", "(a + b) * (a)", 3)
+	runResult(t, "", "match (2) { case n if (n > 1) -> (n) }", 2)
+	runResult(t, functions, "numbers.map(count).join(\",\")", "2,2,2")
+	runResult(t, functions, "Reflect.params(count).join(\" \")", "first ...rest")
+	runResult(t, functions, "Reflect.arity(add)", 3)
+	runResult(t, functions, "Reflect.arity(count)", -1)
+	runResult(t, "fun f([a, b] = [1, 2], {c = 3} = Object()) { return a + b + c; }", "f()", 6)
+	runResult(t, "var a; var rest; [a, ...rest] = [1, 2, 3];", "a + rest.length", 3)
+	runResult(t, "interface I { f(a, b); } class A implements I { f(a, ...rest) {} }", "1", 1)
+	runResult(t, "interface I { f(a, b = 1); } class A implements I { f(a = 0, b = 1, c = 2) {} }", "1", 1)
+
+	errors := []string{
+		"fun f(a, b = 1) {} f();",
+		"fun f(a, b = 1) {} f(1, 2, 3);",
+		"fun f(a, ...rest) {} f();",
+		"fun f(a) {} f(...[1, 2]);",
+		"fun f(a) {} f(...1);",
+		"class A { init(a, b = 1) {} } A(1, 2, 3);",
+	}
+	for _, src := range errors {
+		if !runWith(t, NewInterpreter(false), src) {
+			t.Errorf("expect runtime error for %v", src)
+		}
+	}
+
+	runResErrStmt(t, "fun f(a, ...a) {}")
+	runResErrStmt(t, "interface I { f(a, b); } class A implements I { f(a) {} }")
+	runResErrStmt(t, "interface I { f(...rest); } class A implements I { f(a, b) {} }")
+	parseErrStmt(t, "fun f(a = 1, b) {}")
+	parseErrStmt(t, "fun f(...rest, a) {}")
+	parseErrStmt(t, "fun f(...rest = 1) {}")
+	parseErrStmt(t, "var a; [...a, b] = [1];")
+
+	// the parser goes on after a misplaced default, without reporting other errors.
+	tokens, _ := NewScanner("fun f(a = 1, b) { return b; } var x = 1;").ScanTokens()
+	stmts, hadError := NewParser(tokens).Parse()
+	if !hadError {
+		t.Error("expect parsing error.")
+	}
+	if len(stmts) != 2 || stmts[0] == nil || stmts[1] == nil {
+		t.Errorf("expect the function & the variable to be parsed, but got %v", stmts)
+	}
+}
@@ -0,0 +1,226 @@
+package lox
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"math"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// LoxJSON is the runtime object for the builtin JSON module.
+// JSON objects are decoded into instances of Object, arrays into Array instances.
+// Numbers without a fraction or an exponent are decoded into integers.
+var LoxJSON *LoxClass
+
+// LoxObject is the class of plain objects, e.g. those decoded from JSON.
+var LoxObject *LoxClass
+
+// jsonPosition converts a byte offset in `src` into a line & a column.
+func jsonPosition(src string, offset int64) (int, int) {
+	if offset > int64(len(src)) {
+		offset = int64(len(src))
+	}
+	before := src[:offset]
+	line := strings.Count(before, "\n") + 1
+	column := len(before) - strings.LastIndex(before, "\n")
+	return line, column
+}
+
+func jsonParseError(src string, offset int64, err error) error {
+	line, column := jsonPosition(src, offset)
+	return NewRuntimeError(nil, fmt.Sprintf("JSON.parse(): %v at line %v, column %v.", err, line, column))
+}
+
+// jsonDecode parses `src` into lox values.
+func jsonDecode(interp *Interpreter, src string) interface{} {
+	decoder := json.NewDecoder(strings.NewReader(src))
+	decoder.UseNumber()
+
+	var value interface{}
+	if err := decoder.Decode(&value); err != nil {
+		var syntaxError *json.SyntaxError
+		if errors.As(err, &syntaxError) {
+			// Offset is right after the offending byte.
+			panic(jsonParseError(src, syntaxError.Offset-1, err))
+		}
+		if err == io.EOF {
+			err = errors.New("unexpected end of JSON input")
+		}
+		panic(jsonParseError(src, decoder.InputOffset(), err))
+	}
+
+	// only whitespaces are allowed after the value.
+	if _, err := decoder.Token(); err != io.EOF {
+		panic(jsonParseError(src, decoder.InputOffset(), errors.New("unexpected data after the value")))
+	}
+	return jsonToLox(interp, value)
+}
+
+func jsonToLox(interp *Interpreter, value interface{}) interface{} {
+	switch val := value.(type) {
+	case json.Number:
+		if !strings.ContainsAny(string(val), ".eE") {
+			if integer, err := strconv.Atoi(string(val)); err == nil {
+				return integer
+			}
+		}
+		float, _ := strconv.ParseFloat(string(val), 64)
+		return float
+	case string:
+		interp.alloc(sizeString + len(val))
+		return val
+	case []interface{}:
+		for index, elem := range val {
+			val[index] = jsonToLox(interp, elem)
+		}
+		return newArray(interp, val)
+	case map[string]interface{}:
+		interp.alloc(sizeInstance)
+		object := NewLoxInstance(LoxObject)
+		for key, elem := range val {
+			interp.alloc(sizeSlot + len(key))
+			object.props[key] = jsonToLox(interp, elem)
+		}
+		return object
+	default:
+		// bool & nil.
+		return val
+	}
+}
+
+// jsonEncoder encodes lox values into JSON.
+type jsonEncoder struct {
+	buf      bytes.Buffer
+	indent   string
+	visiting map[*LoxInstance]bool // for detecting cycles.
+}
+
+func (e *jsonEncoder) newline(depth int) {
+	if e.indent != "" {
+		e.buf.WriteByte('\n')
+		e.buf.WriteString(strings.Repeat(e.indent, depth))
+	}
+}
+
+func (e *jsonEncoder) string(s string) {
+	encoder := json.NewEncoder(&e.buf)
+	encoder.SetEscapeHTML(false)
+	encoder.Encode(s)
+	// Encode appends a newline.
+	e.buf.Truncate(e.buf.Len() - 1)
+}
+
+// enter marks `instance` as being encoded, it fails if `instance` is already being encoded.
+func (e *jsonEncoder) enter(instance *LoxInstance) {
+	if e.visiting[instance] {
+		panic(NewRuntimeError(nil, "JSON.stringify(): cyclic structure."))
+	}
+	e.visiting[instance] = true
+}
+
+func (e *jsonEncoder) encode(value interface{}, depth int) {
+	switch val := value.(type) {
+	case nil:
+		e.buf.WriteString("null")
+	case bool:
+		e.buf.WriteString(strconv.FormatBool(val))
+	case int:
+		e.buf.WriteString(strconv.Itoa(val))
+	case float64:
+		if math.IsNaN(val) || math.IsInf(val, 0) {
+			panic(NewRuntimeError(nil, fmt.Sprintf("JSON.stringify(): %v can't be encoded.", val)))
+		}
+		encoded := strconv.FormatFloat(val, 'g', -1, 64)
+		if !strings.ContainsAny(encoded, ".eE") {
+			// keep it a float.
+			encoded += ".0"
+		}
+		e.buf.WriteString(encoded)
+	case string:
+		e.string(val)
+	case *_arrayInsType:
+		e.enter(val.LoxInstance)
+		list := arrayList(val.LoxInstance)
+		e.buf.WriteByte('[')
+		for index, elem := range list {
+			if index > 0 {
+				e.buf.WriteByte(',')
+			}
+			e.newline(depth + 1)
+			e.encode(elem, depth+1)
+		}
+		if len(list) > 0 {
+			e.newline(depth)
+		}
+		e.buf.WriteByte(']')
+		delete(e.visiting, val.LoxInstance)
+	case *LoxInstance:
+		e.enter(val)
+		// sort the keys for a deterministic output.
+		keys := make([]string, 0, len(val.props))
+		for key := range val.props {
+			keys = append(keys, key)
+		}
+		sort.Strings(keys)
+
+		e.buf.WriteByte('{')
+		for index, key := range keys {
+			if index > 0 {
+				e.buf.WriteByte(',')
+			}
+			e.newline(depth + 1)
+			e.string(key)
+			e.buf.WriteByte(':')
+			if e.indent != "" {
+				e.buf.WriteByte(' ')
+			}
+			e.encode(val.props[key], depth+1)
+		}
+		if len(keys) > 0 {
+			e.newline(depth)
+		}
+		e.buf.WriteByte('}')
+		delete(e.visiting, val)
+	default:
+		panic(NewRuntimeError(nil, fmt.Sprintf("JSON.stringify(): %v can't be encoded.", val)))
+	}
+}
+
+// init JSON module. This function will be called when an Interpreter is instantiated.
+func initJSON() {
+	var statics = map[string]Callable{
+		"parse": NewBuiltinFunc(1, func(interp *Interpreter, i *LoxInstance, args ...interface{}) interface{} {
+			return jsonDecode(interp, stringArg("parse", args, 0))
+		}),
+		// stringify(value, indent?) where `indent` is a number of spaces or a string.
+		"stringify": NewBuiltinFunc(-1, func(interp *Interpreter, i *LoxInstance, args ...interface{}) interface{} {
+			checkArgs("stringify", args, 1, 2)
+			encoder := &jsonEncoder{visiting: map[*LoxInstance]bool{}}
+			if len(args) == 2 {
+				switch indent := args[1].(type) {
+				case int:
+					if indent > 0 {
+						encoder.indent = strings.Repeat(" ", indent)
+					}
+				case string:
+					encoder.indent = indent
+				case nil:
+				default:
+					panic(NewRuntimeError(nil, "stringify() expects indent to be a number or a string."))
+				}
+			}
+
+			encoder.encode(args[0], 0)
+			interp.alloc(sizeString + encoder.buf.Len())
+			return encoder.buf.String()
+		}),
+	}
+
+	LoxJSON = NewLoxClass("JSON", nil, statics, nil, nil, nil)
+	LoxObject = NewLoxClass("Object", nil, nil, nil, nil, nil)
+}
@@ -0,0 +1,86 @@
+package lox
+
+import (
+	"fmt"
+	"io"
+	"strings"
+)
+
+// LoxLineReader is the class of objects iterating over the lines of a stream,
+// e.g. a file opened by fs.lines or stdin. `next()` returns the next line
+// without its line break, or nil once the stream is exhausted.
+var LoxLineReader *LoxClass
+
+// lineSource is where a LineReader reads from, e.g. a *bufio.Reader.
+type lineSource interface {
+	ReadString(delim byte) (string, error)
+}
+
+// lineReader is the go side of a LineReader instance, out of reach of scripts.
+type lineReader struct {
+	source lineSource
+	closer io.Closer // called once the source is exhausted or closed, it might be nil.
+}
+
+// newLineReader returns a LineReader instance reading from `source`.
+// `closer` is called once the source is exhausted or closed, it might be nil.
+func newLineReader(interp *Interpreter, source lineSource, closer io.Closer) *LoxInstance {
+	interp.alloc(sizeInstance)
+	instance := NewLoxInstance(LoxLineReader)
+	instance.native = &lineReader{source, closer}
+	return instance
+}
+
+// readLine reads a line from `source`, it returns false at the end of the stream.
+func readLine(interp *Interpreter, source lineSource) (string, bool, error) {
+	line, err := source.ReadString('\n')
+	if err == io.EOF {
+		err = nil
+		if line == "" {
+			return "", false, nil
+		}
+	}
+	if err != nil {
+		return "", false, err
+	}
+
+	line = strings.TrimSuffix(strings.TrimSuffix(line, "\n"), "\r")
+	interp.alloc(sizeString + len(line))
+	return line, true, nil
+}
+
+func closeLineReader(i *LoxInstance) {
+	if reader, ok := i.native.(*lineReader); ok && reader.closer != nil {
+		reader.closer.Close()
+	}
+	i.native = nil
+}
+
+// init LineReader class. This function will be called when an Interpreter is instantiated.
+func initLineReader() {
+	var methods = map[string]Callable{
+		"next": NewBuiltinFunc(0, func(interp *Interpreter, i *LoxInstance, args ...interface{}) interface{} {
+			reader, ok := i.native.(*lineReader)
+			if !ok {
+				return nil
+			}
+
+			line, ok, err := readLine(interp, reader.source)
+			if err != nil {
+				closeLineReader(i)
+				panic(NewRuntimeError(nil, fmt.Sprintf("next(): %v.", err)))
+			}
+			if !ok {
+				closeLineReader(i)
+				return nil
+			}
+			return line
+		}),
+		"close": NewBuiltinFunc(0, func(interp *Interpreter, i *LoxInstance, args ...interface{}) interface{} {
+			closeLineReader(i)
+			return nil
+		}),
+	}
+
+	LoxLineReader = NewLoxClass("LineReader", nil, nil, methods, nil, nil)
+}
@@ -0,0 +1,292 @@
+package lox
+
+import "fmt"
+
+// `match (value) { case 1, 2 -> ... case _ -> ... }` runs the first case one of
+// whose patterns matches `value` & whose guard, e.g. `case n if n > 0 -> ...`, is
+// truthy. Patterns are:
+//   - literals, e.g. `1`, `-2.5`, `"x"` or `nil`, & constants, e.g. `Color.Red`,
+//     matching the values equal to them.
+//   - ranges, e.g. `1..5`, matching the numbers between the bounds, included.
+//   - `_`, matching anything, & names, matching anything & binding it.
+//   - class patterns, e.g. `Point(x, y)`, matching `value is Point`. The patterns
+//     between the parentheses are matched against the fields named after the
+//     parameters of the initializer of `Point`.
+//   - array patterns, e.g. `[first, _, ...rest]`, matching arrays of the same
+//     length, or longer ones if a rest pattern binds the remaining elements.
+//   - object patterns, e.g. `{x, y: [a, b]}`, matching the instances having the
+//     fields, or getters, named by the keys. `{x}` is short for `{x: x}`.
+//
+// The elements of arrays & the fields of objects might have defaults, e.g.
+// `[a, b = 1]` or `{x = 0}`, used if they are nil or missing.
+//
+// The names bound by a case are scoped to it, a case of several patterns can't bind
+// any. As a statement, cases run statements. As an expression, cases evaluate to
+// expressions & no case matching the value is an error.
+
+// Pattern is matched against a value by `match`.
+type Pattern interface {
+	pattern()
+}
+
+// ValuePattern matches the values equal to a literal or a constant.
+type ValuePattern struct {
+	Token *Token // the first token of the pattern, for error reporting.
+	Value Expr
+}
+
+// RangePattern matches the numbers between Low & High, included.
+type RangePattern struct {
+	Low  Expr
+	Dots *Token
+	High Expr
+}
+
+// BindingPattern matches any value & binds it to Name, unless it is `_`.
+type BindingPattern struct {
+	Name *Token
+}
+
+// ClassPattern matches the instances of Class whose fields match Fields.
+type ClassPattern struct {
+	Class  *Variable
+	Fields []Pattern
+}
+
+// ArrayPattern matches the arrays whose elements match Elements. Rest, if any,
+// is matched against the array of the remaining elements.
+type ArrayPattern struct {
+	Bracket  *Token
+	Elements []Pattern
+	Rest     Pattern
+}
+
+// ObjectPattern matches the instances whose fields named by Keys match Values.
+type ObjectPattern struct {
+	Brace  *Token
+	Keys   []*Token
+	Values []Pattern
+}
+
+// DefaultPattern matches Pattern against Default if the value is nil or missing.
+type DefaultPattern struct {
+	Pattern Pattern
+	Default Expr
+}
+
+// VariablePattern matches any value & assigns it to an existing variable, see destructure.go.
+type VariablePattern struct {
+	Variable *Variable
+}
+
+func (*ValuePattern) pattern()    {}
+func (*RangePattern) pattern()    {}
+func (*BindingPattern) pattern()  {}
+func (*ClassPattern) pattern()    {}
+func (*ArrayPattern) pattern()    {}
+func (*ObjectPattern) pattern()   {}
+func (*DefaultPattern) pattern()  {}
+func (*VariablePattern) pattern() {}
+
+// Case is a case of a match. Body is run by match statements, Value is evaluated
+// by match expressions.
+type Case struct {
+	Keyword  *Token
+	Patterns []Pattern
+	Guard    Expr
+	Body     Stmt
+	Value    Expr
+}
+
+// wildcard reports whether `name` is `_`, which matches anything without binding it.
+func wildcard(name *Token) bool {
+	return name.Lexeme == "_"
+}
+
+// VisitMatchExpr runs the first case matching the subject.
+func (i *Interpreter) VisitMatchExpr(expr *Match) interface{} {
+	subject := i.evaluate(expr.Subject)
+
+	for _, c := range expr.Cases {
+		if matched, value := i.matchCase(c, subject); matched {
+			return value
+		}
+	}
+
+	if !expr.Statement {
+		panic(NewRuntimeError(expr.Keyword, "no case matches "+i.stringify(subject)+"."))
+	}
+	return nil
+}
+
+// matchCase runs `c` if it matches `subject`, in an environment binding the names of its pattern.
+func (i *Interpreter) matchCase(c *Case, subject interface{}) (bool, interface{}) {
+	prevEnv := i.environment
+	i.alloc(sizeEnv)
+	i.environment = NewEnvironment(prevEnv)
+
+	// keep the enclosing environment reachable for memory recounts.
+	i.frames = append(i.frames, prevEnv)
+	defer func() {
+		i.environment = prevEnv
+		i.frames = i.frames[:len(i.frames)-1]
+	}()
+
+	matched := false
+	for _, pattern := range c.Patterns {
+		if matched = i.matchPattern(pattern, subject); matched {
+			break
+		}
+	}
+	if !matched || (c.Guard != nil && !truthy(i.evaluate(c.Guard))) {
+		return false, nil
+	}
+
+	if c.Body != nil {
+		i.execute(c.Body)
+		return true, nil
+	}
+	return true, i.evaluate(c.Value)
+}
+
+// matchPattern matches `value` against `pattern`, binding its names in the current environment.
+func (i *Interpreter) matchPattern(pattern Pattern, value interface{}) bool {
+	switch pattern := pattern.(type) {
+	case *ValuePattern:
+		constant := i.evaluate(pattern.Value)
+		if equals, ok := i.overloadEqual(pattern.Token, value, constant); ok {
+			return equals
+		}
+		return equal(value, constant)
+	case *RangePattern:
+		number, ok := numberValue(value)
+		if !ok {
+			return false
+		}
+		low, high, _ := convertFloatOperands(pattern.Dots, i.evaluate(pattern.Low), i.evaluate(pattern.High))
+		return low <= number && number <= high
+	case *BindingPattern:
+		i.bind(pattern.Name, value)
+		return true
+	case *ClassPattern:
+		kind := i.evaluate(pattern.Class)
+		if !isInstance(pattern.Class.Name, value, kind) {
+			return false
+		}
+		if len(pattern.Fields) == 0 {
+			return true
+		}
+
+		instance, _ := value.(*LoxInstance)
+		for index, name := range classFields(pattern, kind) {
+			if !i.matchPattern(pattern.Fields[index], instance.Get(i, name)) {
+				return false
+			}
+		}
+		return true
+	case *ArrayPattern:
+		array, ok := value.(*_arrayInsType)
+		if !ok {
+			return false
+		}
+
+		list := arrayList(array.LoxInstance)
+		if len(list) < requiredElements(pattern) || (pattern.Rest == nil && len(list) > len(pattern.Elements)) {
+			return false
+		}
+		for index, element := range pattern.Elements {
+			var value interface{}
+			if index < len(list) {
+				value = list[index]
+			}
+			if !i.matchPattern(element, value) {
+				return false
+			}
+		}
+		if pattern.Rest != nil {
+			var rest []interface{}
+			if len(list) > len(pattern.Elements) {
+				rest = append(rest, list[len(pattern.Elements):]...)
+			}
+			return i.matchPattern(pattern.Rest, newArray(i, rest))
+		}
+		return true
+	case *ObjectPattern:
+		instance, ok := value.(*LoxInstance)
+		if !ok {
+			return false
+		}
+		for index, key := range pattern.Keys {
+			field, ok := i.field(instance, key)
+			_, hasDefault := pattern.Values[index].(*DefaultPattern)
+			if (!ok && !hasDefault) || !i.matchPattern(pattern.Values[index], field) {
+				return false
+			}
+		}
+		return true
+	case *DefaultPattern:
+		if value == nil {
+			value = i.evaluate(pattern.Default)
+		}
+		return i.matchPattern(pattern.Pattern, value)
+	case *VariablePattern:
+		i.assignments = append(i.assignments, assignment{pattern.Variable, value})
+		return true
+	}
+	return false
+}
+
+// requiredElements returns the number of elements an array pattern requires,
+// the trailing elements with defaults might be missing.
+func requiredElements(pattern *ArrayPattern) int {
+	required := len(pattern.Elements)
+	for required > 0 {
+		if _, ok := pattern.Elements[required-1].(*DefaultPattern); !ok {
+			break
+		}
+		required--
+	}
+	return required
+}
+
+// field returns the field or the getter `name` of an instance, if it has one.
+func (i *Interpreter) field(instance *LoxInstance, name *Token) (interface{}, bool) {
+	if _, ok := instance.props[name.Lexeme]; ok || instance.class.FindGetter(instance, name.Lexeme) != nil {
+		return instance.Get(i, name), true
+	}
+	return nil, false
+}
+
+// bind defines `name` in the current environment, unless it is `_`.
+func (i *Interpreter) bind(name *Token, value interface{}) {
+	if wildcard(name) {
+		return
+	}
+	i.alloc(sizeSlot + len(name.Lexeme))
+	i.environment.Define(name.Lexeme, value)
+}
+
+// classFields returns the names of the fields matched by a class pattern, which
+// are the parameters of the initializer of the class.
+func classFields(pattern *ClassPattern, kind interface{}) []*Token {
+	name := pattern.Class.Name
+	class, ok := kind.(*LoxClass)
+	if !ok {
+		panic(NewRuntimeError(name, "only class patterns of classes can match fields."))
+	}
+
+	initializer, ok := class.findInit().(*LoxFunction)
+	if !ok {
+		panic(NewRuntimeError(name, "class "+class.Name+" has no initializer naming its fields."))
+	}
+	params := initializer.Declaration.Params
+	if len(params) != len(pattern.Fields) {
+		panic(NewRuntimeError(name, fmt.Sprintf("class pattern expects %v fields, but got %v.", len(params), len(pattern.Fields))))
+	}
+
+	fields := make([]*Token, len(params))
+	for index, param := range params {
+		fields[index] = NewToken(TokenIdentifier, param.Lexeme, nil, name.Line)
+	}
+	return fields
+}
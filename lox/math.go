@@ -0,0 +1,115 @@
+package lox
+
+import (
+	"math"
+)
+
+// LoxMath is the runtime object for the builtin Math class.
+var LoxMath *LoxClass
+
+// mathFunc wraps a float function of one argument.
+func mathFunc(name string, fn func(float64) float64) *BuiltInFunc {
+	return NewBuiltinFunc(1, func(interp *Interpreter, i *LoxInstance, args ...interface{}) interface{} {
+		return fn(numberArg(name, args, 0))
+	})
+}
+
+// roundFunc wraps a rounding function. Integers are returned untouched, and
+// floats are converted to integers, unless they are out of the range of integers.
+func roundFunc(name string, fn func(float64) float64) *BuiltInFunc {
+	return NewBuiltinFunc(1, func(interp *Interpreter, i *LoxInstance, args ...interface{}) interface{} {
+		if val, ok := args[0].(int); ok {
+			return val
+		}
+
+		rounded := fn(numberArg(name, args, 0))
+		// NaN & infinities are out of range too. float64(math.MaxInt) rounds up, hence >=.
+		if !(rounded >= math.MinInt && rounded < math.MaxInt) {
+			return rounded
+		}
+		return int(rounded)
+	})
+}
+
+// extremeFunc returns the argument for which `pick` is true comparing to all others.
+func extremeFunc(name string, pick func(x, y float64) bool) *BuiltInFunc {
+	return NewBuiltinFunc(-1, func(interp *Interpreter, i *LoxInstance, args ...interface{}) interface{} {
+		if len(args) == 0 {
+			panic(NewRuntimeError(nil, name+"() expects at least 1 argument."))
+		}
+
+		result := args[0]
+		resultVal := numberArg(name, args, 0)
+		for index := range args[1:] {
+			val := numberArg(name, args, index+1)
+			if math.IsNaN(val) {
+				return math.NaN()
+			}
+			if pick(val, resultVal) {
+				result, resultVal = args[index+1], val
+			}
+		}
+		return result
+	})
+}
+
+// init Math class. This function will be called when an Interpreter is instantiated.
+// Math functions keep integers as integers whenever the result is exact.
+func initMath() {
+	var statics = map[string]Callable{
+		"floor": roundFunc("floor", math.Floor),
+		"ceil":  roundFunc("ceil", math.Ceil),
+		"round": roundFunc("round", math.Round),
+		"abs": NewBuiltinFunc(1, func(interp *Interpreter, i *LoxInstance, args ...interface{}) interface{} {
+			if val, ok := args[0].(int); ok {
+				if val < 0 {
+					return -val
+				}
+				return val
+			}
+			return math.Abs(numberArg("abs", args, 0))
+		}),
+		"sqrt": mathFunc("sqrt", math.Sqrt),
+		"pow": NewBuiltinFunc(2, func(interp *Interpreter, i *LoxInstance, args ...interface{}) interface{} {
+			base, exp := numberArg("pow", args, 0), numberArg("pow", args, 1)
+			result := math.Pow(base, exp)
+
+			_, intBase := args[0].(int)
+			_, intExp := args[1].(int)
+			if intBase && intExp && exp >= 0 && math.Abs(result) < 1<<53 {
+				return int(result)
+			}
+			return result
+		}),
+		"sin": mathFunc("sin", math.Sin),
+		"cos": mathFunc("cos", math.Cos),
+		"tan": mathFunc("tan", math.Tan),
+		"atan2": NewBuiltinFunc(2, func(interp *Interpreter, i *LoxInstance, args ...interface{}) interface{} {
+			return math.Atan2(numberArg("atan2", args, 0), numberArg("atan2", args, 1))
+		}),
+		"log": mathFunc("log", math.Log),
+		"exp": mathFunc("exp", math.Exp),
+		"min": extremeFunc("min", func(x, y float64) bool { return x < y }),
+		"max": extremeFunc("max", func(x, y float64) bool { return x > y }),
+		"isNaN": NewBuiltinFunc(1, func(interp *Interpreter, i *LoxInstance, args ...interface{}) interface{} {
+			val, ok := args[0].(float64)
+			return ok && math.IsNaN(val)
+		}),
+		"isInteger": NewBuiltinFunc(1, func(interp *Interpreter, i *LoxInstance, args ...interface{}) interface{} {
+			switch val := args[0].(type) {
+			case int:
+				return true
+			case float64:
+				return !math.IsInf(val, 0) && val == math.Trunc(val)
+			default:
+				return false
+			}
+		}),
+	}
+
+	LoxMath = NewLoxClass("Math", nil, statics, nil, nil, nil)
+	LoxMath.Fields["PI"] = math.Pi
+	LoxMath.Fields["E"] = math.E
+	LoxMath.Fields["INF"] = math.Inf(1)
+	LoxMath.Fields["NaN"] = math.NaN()
+}
@@ -0,0 +1,208 @@
+package lox
+
+// Memory accounting for Lox-visible objects.
+//
+// The interpreter charges an approximate size every time a script allocates a
+// string, grows an array, adds a property, opens an environment or creates a
+// closure. Freed memory is never reported by the Go runtime, so whenever the
+// charged total gets too high, the interpreter recounts what is still reachable
+// from its environments, much like a garbage collector would.
+
+// approximate sizes in bytes.
+const (
+	sizeSlot     = 16 // an interface{} value stored in a list, a map or an env.
+	sizeString   = 16 // string header, the bytes are charged separately.
+	sizeEnv      = 48 // an Environment and its map.
+	sizeInstance = 48 // a LoxInstance and its props map.
+	sizeFunction = 32 // a closure or a native function.
+	sizeClass    = 96 // a LoxClass and its method tables.
+
+	// the smallest amount of allocations between two recounts.
+	minRecount = 64 * 1024
+)
+
+// Stats reports resource usage of an interpreter.
+type Stats struct {
+	MemoryCurrent int // approximate bytes held by the script.
+	MemoryPeak    int // the highest MemoryCurrent observed.
+	MemoryLimit   int // the memory ceiling, 0 means unlimited.
+}
+
+// memory keeps the accounting state of an interpreter.
+type memory struct {
+	limit     int // 0 means unlimited.
+	live      int // bytes found reachable by the last recount.
+	allocated int // bytes charged since the last recount.
+	peak      int
+}
+
+func (m *memory) current() int {
+	return m.live + m.allocated
+}
+
+// SetMemoryLimit sets the memory ceiling, in bytes, for scripts run by the interpreter.
+// A limit of 0 removes the ceiling.
+func (i *Interpreter) SetMemoryLimit(limit int) {
+	i.memory.limit = limit
+}
+
+// Stats returns the current resource usage of the interpreter.
+func (i *Interpreter) Stats() Stats {
+	i.recount()
+	return Stats{
+		MemoryCurrent: i.memory.current(),
+		MemoryPeak:    i.memory.peak,
+		MemoryLimit:   i.memory.limit,
+	}
+}
+
+// alloc charges `size` bytes to the running script.
+// It panics with an OutOfMemoryError if the limit is exceeded.
+func (i *Interpreter) alloc(size int) {
+	m := &i.memory
+	m.allocated += size
+
+	limited := m.limit > 0 && m.current() > m.limit
+	if limited || m.allocated > m.live && m.allocated > minRecount {
+		i.recount()
+		// the object being allocated is not reachable yet.
+		m.allocated = size
+
+		if m.limit > 0 && m.current() > m.limit {
+			used := m.current()
+			m.allocated = 0
+			panic(NewOutOfMemoryError(used, m.limit))
+		}
+	}
+
+	if current := m.current(); current > m.peak {
+		m.peak = current
+	}
+}
+
+// recount measures the objects reachable from the interpreter's environments.
+func (i *Interpreter) recount() {
+	counter := &memCounter{seen: map[interface{}]bool{}}
+
+	counter.env(i.global)
+	counter.env(i.environment)
+	for _, env := range i.frames {
+		counter.env(env)
+	}
+
+	i.memory.live = counter.total
+	i.memory.allocated = 0
+	if i.memory.live > i.memory.peak {
+		i.memory.peak = i.memory.live
+	}
+}
+
+// memCounter walks Lox values and sums up their approximate sizes.
+type memCounter struct {
+	seen  map[interface{}]bool
+	total int
+}
+
+// visit reports whether `ref` hasn't been counted yet.
+func (c *memCounter) visit(ref interface{}) bool {
+	if c.seen[ref] {
+		return false
+	}
+	c.seen[ref] = true
+	return true
+}
+
+func (c *memCounter) env(env *Environment) {
+	for ; env != nil && c.visit(env); env = env.enclosing {
+		c.total += sizeEnv
+		for name, value := range env.values {
+			c.total += sizeSlot + len(name)
+			c.value(value)
+		}
+	}
+}
+
+func (c *memCounter) callables(fields map[string]Callable) {
+	for name, fn := range fields {
+		c.total += sizeSlot + len(name)
+		c.value(fn)
+	}
+}
+
+func (c *memCounter) value(value interface{}) {
+	switch val := value.(type) {
+	case string:
+		c.total += sizeString + len(val)
+	case []interface{}:
+		c.total += sizeSlot * len(val)
+		for _, elem := range val {
+			c.value(elem)
+		}
+	case *_arrayInsType:
+		c.value(val.LoxInstance)
+	case *LoxInstance:
+		if c.visit(val) {
+			c.total += sizeInstance
+			for name, prop := range val.props {
+				c.total += sizeSlot + len(name)
+				c.value(prop)
+			}
+			for key, prop := range val.privates {
+				c.total += sizeSlot + len(key.name)
+				c.value(prop)
+			}
+			if val.native != nil {
+				c.total += sizeSlot
+				c.value(val.native)
+			}
+			c.value(val.class)
+		}
+	case *LoxClass:
+		if c.visit(val) {
+			c.total += sizeClass
+			c.callables(val.Statics)
+			c.callables(val.Methods)
+			c.callables(val.Getters)
+			c.callables(val.Setters)
+			c.callables(val.StaticGetters)
+			c.callables(val.StaticSetters)
+			for name, field := range val.Fields {
+				c.total += sizeSlot + len(name)
+				c.value(field)
+			}
+			if val.Super != nil {
+				c.value(val.Super)
+			}
+			if val.traits != nil {
+				c.value(val.traits)
+			}
+			for _, trait := range val.Traits {
+				c.value(trait)
+			}
+			for _, iface := range val.Interfaces {
+				c.value(iface)
+			}
+		}
+	case *LoxTrait:
+		if c.visit(val) {
+			c.total += sizeClass
+			c.env(val.Enclosing)
+		}
+	case *LoxInterface:
+		if c.visit(val) {
+			c.total += sizeClass
+		}
+	case *LoxFunction:
+		if c.visit(val) {
+			c.total += sizeFunction
+			c.env(val.Enclosing)
+		}
+	case *BuiltInFunc:
+		if c.visit(val) {
+			c.total += sizeFunction
+			if val.instance != nil {
+				c.value(val.instance)
+			}
+		}
+	}
+}
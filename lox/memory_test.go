@@ -0,0 +1,72 @@
+package lox
+
+import (
+	"testing"
+)
+
+// runWith runs `src` with the given interpreter & reports whether there was a runtime error.
+func runWith(t *testing.T, interpreter *Interpreter, src string) bool {
+	scanner := NewScanner(src)
+	tokens, hadError := scanner.ScanTokens()
+	if hadError {
+		t.Error("scanning error.")
+	}
+
+	parser := NewParser(tokens)
+	stmts, hadError := parser.Parse()
+	if hadError {
+		t.Error("syntax error.")
+	}
+
+	resolver := NewResolver(interpreter)
+	if resolver.Resolve(stmts) {
+		t.Error("resolve error.")
+	}
+
+	return interpreter.Interprete(stmts)
+}
+
+func TestMemoryLimit(t *testing.T) {
+	interpreter := NewInterpreter(false)
+	interpreter.SetMemoryLimit(64 * 1024)
+
+	hadRuntimeError := runWith(t, interpreter, "var a = []; while (true) { a.append(\"item\"); }")
+	if !hadRuntimeError {
+		t.Fatal("expect out of memory error.")
+	}
+
+	if _, ok := interpreter.LastError().(*OutOfMemoryError); !ok {
+		t.Errorf("expect *OutOfMemoryError, but got %v", interpreter.LastError())
+	}
+
+	stats := interpreter.Stats()
+	if stats.MemoryPeak > stats.MemoryLimit {
+		t.Errorf("expect peak %v to be within limit %v", stats.MemoryPeak, stats.MemoryLimit)
+	}
+}
+
+func TestMemoryGarbage(t *testing.T) {
+	interpreter := NewInterpreter(false)
+	interpreter.SetMemoryLimit(64 * 1024)
+
+	// every string built here is garbage by the next iteration.
+	src := "for (var i = 0; i < 10000; i += 1) { var s = \"some text\" + \" and more\"; }"
+	if runWith(t, interpreter, src) {
+		t.Fatalf("unexpected runtime error: %v", interpreter.LastError())
+	}
+}
+
+func TestMemoryStats(t *testing.T) {
+	interpreter := NewInterpreter(false)
+	before := interpreter.Stats()
+
+	runWith(t, interpreter, "var a = [1, 2, 3, 4, 5, 6, 7, 8]; var s = \"abc\" + \"def\";")
+	after := interpreter.Stats()
+
+	if after.MemoryCurrent <= before.MemoryCurrent {
+		t.Errorf("expect usage to grow, but got %v -> %v", before.MemoryCurrent, after.MemoryCurrent)
+	}
+	if after.MemoryPeak < after.MemoryCurrent {
+		t.Errorf("expect peak %v >= current %v", after.MemoryPeak, after.MemoryCurrent)
+	}
+}
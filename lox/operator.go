@@ -0,0 +1,150 @@
+package lox
+
+import "fmt"
+
+// Classes overload operators by defining special methods, e.g. `a + b` calls
+// `a.__add__(b)`. If the left operand doesn't overload an arithmetic operator,
+// the reflected method of the right operand is called, e.g. `1 + b` calls `b.__radd__(1)`.
+// Comparisons call `__lt__`, `__gt__`, `__le__` or `__ge__`. A class defining
+// only `__lt__` compares with all of them: `a >= b` is `!a.__lt__(b)`, `a <= b` is
+// `a.__lt__(b) or a == b` & `a > b` is `!a.__lt__(b) and a != b`. If the left operand
+// doesn't compare, the right one does with the reflected comparison, e.g. `1 < b`
+// is `b > 1`. `a != b` is `!(a == b)`.
+
+// arithmeticMethods maps arithmetic operators to the special methods overloading them.
+var arithmeticMethods = map[TokenType]string{
+	TokenPlus:    "add",
+	TokenMinus:   "sub",
+	TokenStar:    "mul",
+	TokenSlash:   "div",
+	TokenPercent: "mod",
+}
+
+// comparisonMethods maps comparison operators to the special methods overloading them.
+var comparisonMethods = map[TokenType]string{
+	TokenLess:         "__lt__",
+	TokenGreater:      "__gt__",
+	TokenLessEqual:    "__le__",
+	TokenGreaterEqual: "__ge__",
+}
+
+// reflectedComparisons maps comparison operators to the ones swapping their operands, e.g. `a < b` is `b > a`.
+var reflectedComparisons = map[TokenType]TokenType{
+	TokenLess:         TokenGreater,
+	TokenGreater:      TokenLess,
+	TokenLessEqual:    TokenGreaterEqual,
+	TokenGreaterEqual: TokenLessEqual,
+}
+
+// findOperator returns the special method `name` of `value`, bound to it.
+// Only instances of lox classes overload operators.
+func (i *Interpreter) findOperator(value interface{}, name string) Callable {
+	instance, ok := value.(*LoxInstance)
+	if !ok {
+		return nil
+	}
+
+	method := instance.class.FindMethod(instance, name)
+	if method != nil {
+		// binding creates a closure.
+		i.alloc(sizeEnv + sizeFunction)
+	}
+	return method
+}
+
+// callOperator calls a special method with `args`.
+func (i *Interpreter) callOperator(token *Token, method Callable, name string, args ...interface{}) interface{} {
+	if min, max := arityRange(method); min > len(args) || (max != -1 && max < len(args)) {
+		panic(NewRuntimeError(token, fmt.Sprintf("%v() must take %v arguments, but it takes %v.", name, len(args), arityString(min, max))))
+	}
+	return method.Call(i, args...)
+}
+
+// overloadBinary evaluates a binary operator overloaded by one of the operands.
+// It returns false if none of the operands overloads `operator`.
+func (i *Interpreter) overloadBinary(operator *Token, left, right interface{}) (interface{}, bool) {
+	_, leftIsInstance := left.(*LoxInstance)
+	_, rightIsInstance := right.(*LoxInstance)
+	if !leftIsInstance && !rightIsInstance {
+		return nil, false
+	}
+
+	switch operator.Type {
+	case TokenEqualEqual, TokenBangEqual:
+		equals, ok := i.overloadEqual(operator, left, right)
+		if ok && operator.Type == TokenBangEqual {
+			return !equals, true
+		}
+		return equals, ok
+	case TokenLess, TokenGreater, TokenLessEqual, TokenGreaterEqual:
+		if result, ok := i.overloadCompare(operator, operator.Type, left, right); ok {
+			return result, true
+		}
+		return i.overloadCompare(operator, reflectedComparisons[operator.Type], right, left)
+	}
+
+	name, ok := arithmeticMethods[operator.Type]
+	if !ok {
+		return nil, false
+	}
+	if method := i.findOperator(left, "__"+name+"__"); method != nil {
+		return i.callOperator(operator, method, "__"+name+"__", right), true
+	}
+	if method := i.findOperator(right, "__r"+name+"__"); method != nil {
+		return i.callOperator(operator, method, "__r"+name+"__", left), true
+	}
+	return nil, false
+}
+
+// overloadEqual calls `__eq__` of the left operand, or of the right one.
+func (i *Interpreter) overloadEqual(operator *Token, left, right interface{}) (bool, bool) {
+	if method := i.findOperator(left, "__eq__"); method != nil {
+		return truthy(i.callOperator(operator, method, "__eq__", right)), true
+	}
+	if method := i.findOperator(right, "__eq__"); method != nil {
+		return truthy(i.callOperator(operator, method, "__eq__", left)), true
+	}
+	return false, false
+}
+
+// overloadCompare evaluates `left comparison right` with the special methods of
+// `left`, deriving the comparison from `__lt__` if `left` doesn't overload it.
+func (i *Interpreter) overloadCompare(operator *Token, comparison TokenType, left, right interface{}) (interface{}, bool) {
+	name := comparisonMethods[comparison]
+	if method := i.findOperator(left, name); method != nil {
+		return truthy(i.callOperator(operator, method, name, right)), true
+	}
+
+	method := i.findOperator(left, "__lt__")
+	if method == nil {
+		return nil, false
+	}
+	less := truthy(i.callOperator(operator, method, "__lt__", right))
+	equals := func() bool {
+		if equals, ok := i.overloadEqual(operator, left, right); ok {
+			return equals
+		}
+		return equal(left, right)
+	}
+
+	switch comparison {
+	case TokenGreater:
+		return !less && !equals(), true
+	case TokenLessEqual:
+		return less || equals(), true
+	case TokenGreaterEqual:
+		return !less, true
+	}
+	return less, true
+}
+
+// overloadUnary evaluates `-value` if value overloads `__neg__`.
+func (i *Interpreter) overloadUnary(operator *Token, value interface{}) (interface{}, bool) {
+	if operator.Type != TokenMinus {
+		return nil, false
+	}
+	if method := i.findOperator(value, "__neg__"); method != nil {
+		return i.callOperator(operator, method, "__neg__"), true
+	}
+	return nil, false
+}
@@ -82,8 +82,12 @@ func (p *Parser) synchronize() {
 
 		switch p.peek().Type {
 		case TokenClass,
+			TokenTrait,
+			TokenInterface,
+			TokenEnum,
 			TokenFun,
 			TokenVar,
+			TokenConst,
 			TokenFor,
 			TokenIf,
 			TokenWhile,
@@ -99,16 +103,24 @@ func (p *Parser) synchronize() {
 }
 
 // program			-> declaration* EOF ;
-// declaration		-> varDeclaration | funDeclaration | classDeclaration ;
-// classDelaration	-> "class" IDENTIFIER ( "<" identifier )? "{" ( function | getter | setter )* "}" ;
+// declaration		-> varDeclaration | constDeclaration | funDeclaration | classDeclaration | traitDeclaration
+//						| interfaceDeclaration | enumDeclaration ;
+// classDelaration	-> "class" IDENTIFIER ( "<" identifier )? ( "with" identifier ( "," identifier )* )?
+//						( "implements" identifier ( "," identifier )* )? "{" ( function | getter | setter )* "}" ;
+// enumDeclaration	-> "enum" IDENTIFIER "{" IDENTIFIER ( "," IDENTIFIER )* ","? ( ";" ( function | getter | setter )* )? "}" ;
+// traitDeclaration	-> "trait" IDENTIFIER "{" ( function | getter | setter )* "}" ;
+// interfaceDeclaration	-> "interface" IDENTIFIER "{" ( ( "get" IDENTIFIER | IDENTIFIER "(" parameters? ")" ) ";" )* "}" ;
 // getter			-> "get" block ;
 // setter			-> "set" "(" identifier ")" block ;
 // funDeclaration	-> "fun" function ;
 // function			-> IDENTIFIER "(" parameters? ")" block ;
-// parameters		-> IDENTIFIER ( "," IDENTIFIER )* ;
-// varDeclaration	-> "var" nameDeclaration ("," nameDeclaration)* ";"? ;
+// parameters		-> parameter ( "," parameter )* ( "," "..." IDENTIFIER )? | "..." IDENTIFIER ;
+// parameter		-> ( IDENTIFIER | arrayPattern | objectPattern ) ( "=" expression )? ;
+// varDeclaration	-> "var" ( nameDeclaration ("," nameDeclaration)* | destructuring ) ";"? ;
+// constDeclaration	-> "const" ( IDENTIFIER "=" expression ( "," IDENTIFIER "=" expression )* | destructuring ) ";"? ;
+// destructuring	-> ( arrayPattern | objectPattern ) "=" expression ;
 // nameDeclaration	-> IDENTIFIER ( "=" expression ) ;
-// statement		-> block | expreStmt | printStmt | "break" ";"? | returnStmt ;
+// statement		-> block | expreStmt | printStmt | "break" ";"? | returnStmt | matchStmt ;
 // block			-> "{" declaration* "}" ;
 // printStmt		-> "print" expression ";"? ;
 // expreStmt		-> expression ";"? ;
@@ -116,21 +128,36 @@ func (p *Parser) synchronize() {
 // IfStmt			-> "if" "(" expression ")" statement ( "else" statement  )? ;
 // returnStmt		-> "return" expression? ";"? ;
 // WhileStmt		-> "while" "(" expression ")" statement
+// matchStmt		-> "match" "(" expression ")" "{" ( "case" patterns "->" statement )* "}" ;
+// patterns			-> pattern ( "," pattern )* ( "if" expression )? ;
+// pattern			-> literal ( ".." literal )? | IDENTIFIER ( "." IDENTIFIER )* | IDENTIFIER "(" ( pattern ( "," pattern )* )? ")"
+//						| arrayPattern | objectPattern ;
+// arrayPattern		-> "[" ( element ( "," element )* )? ( ","? "..." IDENTIFIER )? "]" ;
+// objectPattern	-> "{" ( field ( "," field )* )? "}" ;
+// field			-> IDENTIFIER ( ":" element | "=" expression )? ;
+// element			-> pattern ( "=" expression )? ;
+// literal			-> "-"? NUMBER | STRING | "true" | "false" | "nil" ;
 // expression		-> assignment ;
-// asignment		-> ( call "." )? identifier ( "[" exression "]" )? assignmentOp expression | logical_or ;
-// assignmentOp		-> "=" | "+=" | "-=" | "*=" | "/=" ;
+// asignment		-> ( call "." )? identifier ( "[" exression "]" )? assignmentOp expression
+//						| arrayliteral "=" expression | conditional ;
+// assignmentOp		-> "=" | "+=" | "-=" | "*=" | "/=" | "%=" ;
+// conditional		-> coalesce ( "?" expression ":" conditional )? ;
+// coalesce			-> logical_or ( "??" logical_or )* ;
 // logical_or		-> logical_and ( "or" logical_and )* ;
 // logical_and		-> equality ( "and" equality )* ;
 // equality			-> comparison ( ( "==" | "!=" ) comparison )* ;
-// comparison		-> addition ( ( "<" | "<=" | ">" | ">=" ) addition )* ;
+// comparison		-> addition ( ( "<" | "<=" | ">" | ">=" | "is" ) addition )* ;
 // addition			-> multiplication ( ( "+" | "-" ) multiplication )* ;
 // multiplication 	-> unary ( ( "*" | "/" | "%" ) unary )* ;
 // unary			-> ( "!" | "-" ) unary | call ;
-// call				-> primary ( "(" expression ( "," expression )* "}" | "." IDENTIFIER | "[" expression "]" )* ;
+// call				-> primary ( "?."? "(" argument ( "," argument )* "}" | ( "." | "?." ) IDENTIFIER
+//						| ( "[" | "?[" ) expression "]" )* ;
 // primary 			-> IDENTIFIER | NUMBER | STRING | "(" expression ")" | arrayliteral
-//						| lambda | "super" "." identifier | "this" | "true" | "false" | "nil" ;
-// arrayliteral		-> "[" expr ("," expr)* "]" ;
-// lambda			-> "(" parameters ")" "->" statement ;
+//						| lambda | match | "super" "." identifier | "this" | "true" | "false" | "nil" ;
+// match			-> "match" "(" expression ")" "{" ( "case" patterns "->" expression ( "," | ";" )? )* "}" ;
+// arrayliteral		-> "[" argument ("," argument)* "]" ;
+// argument			-> "..."? expression ;
+// lambda			-> "(" parameters? ")" "->" ( block | expression ) ;
 
 // Parse is the entry point of Parser.
 func (p *Parser) Parse() ([]Stmt, bool) {
@@ -142,6 +169,12 @@ func (p *Parser) Parse() ([]Stmt, bool) {
 	return stmts, p.hadError
 }
 
+// error reports a parsing error without panicking, for errors the parser can go on after.
+func (p *Parser) error(token *Token, message string) {
+	fmt.Println(NewLoxError(token, message).Error())
+	p.hadError = true
+}
+
 func (p *Parser) declaration() Stmt {
 	defer func() {
 		if val := recover(); val != nil {
@@ -157,8 +190,16 @@ func (p *Parser) declaration() Stmt {
 	switch {
 	case p.match(TokenClass):
 		return p.classDeclaration()
+	case p.match(TokenTrait):
+		return p.traitDeclaration()
+	case p.match(TokenInterface):
+		return p.interfaceDeclaration()
+	case p.match(TokenEnum):
+		return p.enumDeclaration()
 	case p.match(TokenVar):
 		return p.varDeclaration()
+	case p.match(TokenConst):
+		return p.constDeclaration()
 	case p.match(TokenFun):
 		return p.function("function")
 	default:
@@ -169,10 +210,16 @@ func (p *Parser) declaration() Stmt {
 func (p *Parser) classDeclaration() Stmt {
 	var className *Token
 	var super *Variable
+	var traits = make([]*Variable, 0)
+	var interfaces = make([]*Variable, 0)
 	var statics = make([]*Function, 0)
 	var functions = make([]*Function, 0)
 	var getters = make([]*Function, 0)
 	var setters = make([]*Function, 0)
+	var staticGetters = make([]*Function, 0)
+	var staticSetters = make([]*Function, 0)
+	// static fields & static blocks, in the order they are declared.
+	var initializers = make([]Stmt, 0)
 
 	className = p.consume(TokenIdentifier, "expect class name to be an identifier.")
 
@@ -181,6 +228,16 @@ func (p *Parser) classDeclaration() Stmt {
 		super, _ = NewVariable(superName).(*Variable)
 	}
 
+	if p.match(TokenWith) {
+		traits = p.names("expect trait name.")
+	}
+
+	// "implements" isn't a keyword, so that it also names the builtin function.
+	if p.check(TokenIdentifier) && p.peek().Lexeme == "implements" {
+		p.advance()
+		interfaces = p.names("expect interface name.")
+	}
+
 	p.consume(TokenLeftBrace, "expect '{' after class name.")
 
 	for !p.check(TokenRightBrace) {
@@ -192,8 +249,26 @@ func (p *Parser) classDeclaration() Stmt {
 			setter, _ := p.setter().(*Function)
 			setters = append(setters, setter)
 		case p.match(TokenStatic):
-			static := p.function("method").(*Function)
-			statics = append(statics, static)
+			switch {
+			case p.match(TokenGetter):
+				getter, _ := p.getter().(*Function)
+				staticGetters = append(staticGetters, getter)
+			case p.match(TokenSetter):
+				setter, _ := p.setter().(*Function)
+				staticSetters = append(staticSetters, setter)
+			case p.match(TokenVar):
+				keyword := p.previous()
+				initializer := p.varDeclaration()
+				if _, ok := initializer.(*VarDestructure); ok {
+					panic(NewLoxError(keyword, "static fields can't be destructured."))
+				}
+				initializers = append(initializers, initializer)
+			case p.match(TokenLeftBrace):
+				initializers = append(initializers, NewBlock(p.block()))
+			default:
+				static := p.function("method").(*Function)
+				statics = append(statics, static)
+			}
 		default:
 			function, _ := p.function("method").(*Function)
 			functions = append(functions, function)
@@ -201,7 +276,114 @@ func (p *Parser) classDeclaration() Stmt {
 	}
 
 	p.consume(TokenRightBrace, "expect '}' after class declaration.")
-	return NewClass(className, super, statics, functions, getters, setters)
+	return NewClass(className, super, traits, interfaces, statics, functions, getters, setters, staticGetters, staticSetters, initializers)
+}
+
+func (p *Parser) traitDeclaration() Stmt {
+	var functions = make([]*Function, 0)
+	var getters = make([]*Function, 0)
+	var setters = make([]*Function, 0)
+
+	traitName := p.consume(TokenIdentifier, "expect trait name to be an identifier.")
+	p.consume(TokenLeftBrace, "expect '{' after trait name.")
+
+	for !p.check(TokenRightBrace) {
+		switch {
+		case p.match(TokenGetter):
+			getter, _ := p.getter().(*Function)
+			getters = append(getters, getter)
+		case p.match(TokenSetter):
+			setter, _ := p.setter().(*Function)
+			setters = append(setters, setter)
+		case p.check(TokenStatic):
+			panic(NewLoxError(p.peek(), "a trait cannot have static members."))
+		default:
+			function, _ := p.function("method").(*Function)
+			functions = append(functions, function)
+		}
+	}
+
+	p.consume(TokenRightBrace, "expect '}' after trait declaration.")
+	return NewTrait(traitName, functions, getters, setters)
+}
+
+func (p *Parser) enumDeclaration() Stmt {
+	var members = make([]*Token, 0)
+	var functions = make([]*Function, 0)
+	var getters = make([]*Function, 0)
+	var setters = make([]*Function, 0)
+
+	enumName := p.consume(TokenIdentifier, "expect enum name to be an identifier.")
+	p.consume(TokenLeftBrace, "expect '{' after enum name.")
+
+	for {
+		members = append(members, p.consume(TokenIdentifier, "expect enum member name."))
+		if !p.match(TokenComma) || p.check(TokenSemi) || p.check(TokenRightBrace) {
+			break
+		}
+	}
+
+	// methods follow the members.
+	if p.match(TokenSemi) {
+		for !p.check(TokenRightBrace) {
+			switch {
+			case p.match(TokenGetter):
+				getter, _ := p.getter().(*Function)
+				getters = append(getters, getter)
+			case p.match(TokenSetter):
+				setter, _ := p.setter().(*Function)
+				setters = append(setters, setter)
+			case p.check(TokenStatic):
+				panic(NewLoxError(p.peek(), "an enum cannot have static members."))
+			default:
+				function, _ := p.function("method").(*Function)
+				functions = append(functions, function)
+			}
+		}
+	}
+
+	p.consume(TokenRightBrace, "expect '}' after enum declaration.")
+	return NewEnum(enumName, members, functions, getters, setters)
+}
+
+// names parses a comma separated list of identifiers, e.g. the traits composed by a class.
+func (p *Parser) names(message string) []*Variable {
+	names := make([]*Variable, 0)
+	for {
+		name, _ := NewVariable(p.consume(TokenIdentifier, message)).(*Variable)
+		names = append(names, name)
+
+		if !p.match(TokenComma) {
+			return names
+		}
+	}
+}
+
+// interfaceDeclaration parses the members an interface requires, which have no body.
+func (p *Parser) interfaceDeclaration() Stmt {
+	var methods = make([]*Function, 0)
+	var getters = make([]*Function, 0)
+
+	interfaceName := p.consume(TokenIdentifier, "expect interface name to be an identifier.")
+	p.consume(TokenLeftBrace, "expect '{' after interface name.")
+
+	for !p.check(TokenRightBrace) {
+		if p.match(TokenGetter) {
+			name := p.consume(TokenIdentifier, "expect identifier after 'get'")
+			getters = append(getters, NewFunction(name, nil, nil, nil, nil).(*Function))
+		} else {
+			name := p.consume(TokenIdentifier, "expect method name.")
+			p.consume(TokenLeftParen, "expect '(' after method name.")
+			method, _ := p.parameters()
+			p.consume(TokenRightParen, "expect ')' after param list.")
+			method.Name = name
+			methods = append(methods, method)
+		}
+		p.consume(TokenSemi, "expect ';' after interface member.")
+	}
+
+	p.consume(TokenRightBrace, "expect '}' after interface declaration.")
+	return NewInterface(interfaceName, methods, getters)
 }
 
 // we don't add a new type for  getter because it is a function in essence.
@@ -209,7 +391,7 @@ func (p *Parser) getter() Stmt {
 	name := p.consume(TokenIdentifier, "expect identifier after 'get'")
 	p.consume(TokenLeftBrace, "expect '{' after identifier of getter.")
 	body := p.block()
-	return NewFunction(name, nil, body)
+	return NewFunction(name, nil, nil, nil, body)
 }
 
 // same as getter.
@@ -228,44 +410,111 @@ func (p *Parser) setter() Stmt {
 	p.consume(TokenLeftBrace, "expect '{' before body.")
 
 	body = p.block()
-	return NewFunction(name, []*Token{param}, body)
+	return NewFunction(name, []*Token{param}, nil, nil, body)
 }
 
 func (p *Parser) function(kind string) Stmt {
 	var (
-		name   *Token
-		params []*Token
-		body   []Stmt
+		name         *Token
+		function     *Function
+		destructures []Stmt
 	)
 
 	name = p.consume(TokenIdentifier, "expect IDENTIFIER after 'fun'.")
-	params = make([]*Token, 0)
 
 	// parameters.
 	p.consume(TokenLeftParen, "expect '(' after IDENTIFIER.")
-	if !p.check(TokenRightParen) {
-		for true {
-			param := p.consume(TokenIdentifier, "expect TokenIdentifier as param.")
-			params = append(params, param)
+	function, destructures = p.parameters()
+	p.consume(TokenRightParen, "expect ')' after param list.")
 
-			if len(params) > 8 {
-				panic(NewLoxError(p.peek(), "cannot have more than 8 parameters."))
-			}
-			if !p.match(TokenComma) {
-				break
-			}
+	// body, which destructures the parameters first.
+	p.consume(TokenLeftBrace, "expect '{' before function body.")
+	function.Name = name
+	function.Body = append(destructures, p.block()...)
+	return function
+}
+
+// parameters parses the parameters of a function, up to the closing ')', into a
+// function declaration without name & body. Parameters might have defaults, the
+// last one might be a rest parameter, e.g. `(a, b = 1, ...rest)`. A destructured
+// parameter is named after its pattern & destructured by the returned declarations.
+func (p *Parser) parameters() (*Function, []Stmt) {
+	function := &Function{Params: make([]*Token, 0)}
+	destructures := make([]Stmt, 0)
+	if p.check(TokenRightParen) {
+		return function, destructures
+	}
+
+	hasDefaults := false
+	for true {
+		// the rest parameter comes last.
+		if p.match(TokenEllipsis) {
+			function.Rest = p.consume(TokenIdentifier, "expect a name after '...'.")
+			break
+		}
+
+		var param *Token
+		if p.check(TokenLeftBracket) || p.check(TokenLeftBrace) {
+			start := p.current
+			pattern := p.declarablePattern()
+			param = NewToken(TokenIdentifier, p.source(start), nil, p.tokens[start].Line)
+			destructures = append(destructures, NewVarDestructure(param, pattern, NewVariable(param), false))
+		} else {
+			param = p.consume(TokenIdentifier, "expect TokenIdentifier as param.")
+		}
+		function.Params = append(function.Params, param)
+
+		var value Expr
+		if p.match(TokenEqual) {
+			value = p.expression()
+			hasDefaults = true
+		} else if hasDefaults {
+			p.error(param, "parameters without defaults can't follow those with defaults.")
+		}
+		function.Defaults = append(function.Defaults, value)
+
+		if len(function.Params) > 8 {
+			panic(NewLoxError(p.peek(), "cannot have more than 8 parameters."))
+		}
+		if !p.match(TokenComma) {
+			break
 		}
 	}
-	p.consume(TokenRightParen, "expect ')' after param list.")
+	return function, destructures
+}
 
-	// body.
-	p.consume(TokenLeftBrace, "expect '{' before function body.")
-	body = p.block()
-	return NewFunction(name, params, body)
+// source returns the source of the tokens from `start` to the current one.
+func (p *Parser) source(start int) string {
+	source := ""
+	for _, token := range p.tokens[start:p.current] {
+		switch token.Type {
+		case TokenComma, TokenColon:
+			source += token.Lexeme + " "
+		case TokenEqual:
+			source += " = "
+		default:
+			source += token.Lexeme
+		}
+	}
+	return source
 }
 
 func (p *Parser) varDeclaration() Stmt {
-	varDec := p.nameDeclaration()
+	return p.declarations(false)
+}
+
+// constDeclaration parses constants, which must be initialized.
+func (p *Parser) constDeclaration() Stmt {
+	return p.declarations(true)
+}
+
+// declarations parses a list of variables or constants, or a destructuring declaration.
+func (p *Parser) declarations(constant bool) Stmt {
+	if p.check(TokenLeftBracket) || p.check(TokenLeftBrace) {
+		return p.destructuring(constant)
+	}
+
+	varDec := p.nameDeclaration(constant)
 
 	if p.check(TokenSemi) {
 		p.advance()
@@ -276,7 +525,7 @@ func (p *Parser) varDeclaration() Stmt {
 	varDecs = append(varDecs, varDec)
 	for p.check(TokenComma) {
 		p.advance()
-		varDec = p.nameDeclaration()
+		varDec = p.nameDeclaration(constant)
 		varDecs = append(varDecs, varDec)
 	}
 	if p.check(TokenSemi) {
@@ -286,7 +535,7 @@ func (p *Parser) varDeclaration() Stmt {
 }
 
 // a helper function for dealing with multi-var declarations.
-func (p *Parser) nameDeclaration() *Var {
+func (p *Parser) nameDeclaration(constant bool) *Var {
 	var (
 		name        *Token
 		initializer Expr
@@ -295,13 +544,41 @@ func (p *Parser) nameDeclaration() *Var {
 	name = p.consume(TokenIdentifier, "expect variable name.")
 	if p.match(TokenEqual) {
 		initializer = p.expression()
+	} else if constant {
+		panic(NewLoxError(p.peek(), "expect '=' after constant name."))
 	}
 
 	// We convert it eagerly because we know it's a *Var.
-	varDec, _ := NewVar(name, initializer).(*Var)
+	varDec, _ := NewVar(name, initializer, constant).(*Var)
 	return varDec
 }
 
+// destructuring parses the declaration of the names bound by a pattern.
+func (p *Parser) destructuring(constant bool) Stmt {
+	keyword := p.previous()
+	pattern := p.declarablePattern()
+	p.consume(TokenEqual, "expect '=' after pattern.")
+	initializer := p.expression()
+
+	if p.check(TokenComma) {
+		panic(NewLoxError(p.peek(), "a destructuring declaration can't declare other names."))
+	}
+	if p.check(TokenSemi) {
+		p.advance()
+	}
+	return NewVarDestructure(keyword, pattern, initializer, constant)
+}
+
+// declarablePattern parses a pattern which only binds names.
+func (p *Parser) declarablePattern() Pattern {
+	token := p.peek()
+	pattern := p.pattern()
+	if !declarable(pattern) {
+		panic(NewLoxError(token, "only names, array & object patterns can be declared."))
+	}
+	return pattern
+}
+
 func (p *Parser) statement() Stmt {
 	switch {
 	case p.match(TokenBreak):
@@ -330,6 +607,12 @@ func (p *Parser) statement() Stmt {
 		return NewControl(keyword, ControlReturn, value)
 	case p.match(TokenLeftBrace):
 		return NewBlock(p.block())
+	case p.match(TokenMatch):
+		stmt := NewExpression(p.matchExpr(true))
+		if p.check(TokenSemi) {
+			p.advance()
+		}
+		return stmt
 	case p.match(TokenWhile):
 		return p.whileStmt()
 	default:
@@ -461,10 +744,10 @@ func (p *Parser) expression() Expr {
 }
 
 func (p *Parser) assignment() Expr {
-	expr := p.or()
+	expr := p.conditional()
 
 	// call assignment recursively because it's right associative.
-	if p.match(TokenEqual, TokenPlusEqual, TokenMinusEqual, TokenStarEqual, TokenSlashEqual) {
+	if p.match(TokenEqual, TokenPlusEqual, TokenMinusEqual, TokenStarEqual, TokenSlashEqual, TokenPercentEqual) {
 		operator := p.previous()
 		value := p.assignment()
 
@@ -472,11 +755,15 @@ func (p *Parser) assignment() Expr {
 			name := varExpr.Name
 			return NewAssign(name, operator, value)
 		} else if getExpr, ok := expr.(*Get); ok {
-			return NewSet(getExpr.Object, getExpr.Name, value)
+			return NewSet(getExpr.Object, getExpr.Name, operator, value)
 		} else if subscript, ok := expr.(*Subscript); ok {
 			// TODO: fix fake token.
-			keyToken := NewToken(-1, "", subscript.Key, -1)
-			return NewSet(subscript.Object, keyToken, value)
+			keyToken := NewToken(-1, subscript.Bracket.Lexeme, subscript.Key, subscript.Bracket.Line)
+			return NewSet(subscript.Object, keyToken, operator, value)
+		} else if superExpr, ok := expr.(*Super); ok {
+			return NewSuperSet(superExpr.Keyword, superExpr.Method, operator, value)
+		} else if array, ok := expr.(*Array); ok && operator.Type == TokenEqual {
+			return NewDestructure(p.target(array), operator, value)
 		}
 		errmsg := "invalid assign target."
 		panic(NewLoxError(operator, errmsg))
@@ -485,6 +772,57 @@ func (p *Parser) assignment() Expr {
 	return expr
 }
 
+// target converts the left side of a destructuring assignment, e.g. `[a, b = 1]`, to a pattern.
+func (p *Parser) target(expr Expr) Pattern {
+	switch expr := expr.(type) {
+	case *Variable:
+		if wildcard(expr.Name) {
+			return &BindingPattern{expr.Name}
+		}
+		return &VariablePattern{expr}
+	case *Assign:
+		if expr.Operator.Type == TokenEqual {
+			variable, _ := NewVariable(expr.Name).(*Variable)
+			return &DefaultPattern{p.target(variable), expr.Value}
+		}
+	case *Array:
+		elements := make([]Pattern, 0, len(expr.Elements))
+		for index, element := range expr.Elements {
+			// `...rest` is the last target.
+			if spread, ok := element.(*Spread); ok && index == len(expr.Elements)-1 {
+				return &ArrayPattern{nil, elements, p.target(spread.Expression)}
+			}
+			elements = append(elements, p.target(element))
+		}
+		return &ArrayPattern{nil, elements, nil}
+	}
+	panic(NewLoxError(p.previous(), "invalid assign target."))
+}
+
+func (p *Parser) conditional() Expr {
+	expr := p.coalesce()
+
+	if p.match(TokenQuestion) {
+		thenBranch := p.expression()
+		p.consume(TokenColon, "expect ':' after then branch of conditional expression.")
+		elseBranch := p.conditional()
+		expr = NewConditional(expr, thenBranch, elseBranch)
+	}
+	return expr
+}
+
+// coalesce parses `a ?? b`, which is `b` only if `a` is nil.
+func (p *Parser) coalesce() Expr {
+	expr := p.or()
+
+	for p.match(TokenQuestionQuestion) {
+		operator := p.previous()
+		right := p.or()
+		expr = NewLogical(expr, operator, right)
+	}
+	return expr
+}
+
 func (p *Parser) or() Expr {
 	expr := p.and()
 
@@ -522,7 +860,7 @@ func (p *Parser) equality() Expr {
 func (p *Parser) comparison() Expr {
 	expr := p.addition()
 
-	if p.match(TokenGreater, TokenGreaterEqual, TokenLess, TokenLessEqual) {
+	if p.match(TokenGreater, TokenGreaterEqual, TokenLess, TokenLessEqual, TokenIs) {
 		operator := p.previous()
 		right := p.comparison()
 		expr = NewBinary(expr, operator, right)
@@ -571,36 +909,71 @@ func (p *Parser) call() Expr {
 	)
 
 	expr = p.primary()
+	// whether the chain has optional links, which short-circuit it.
+	optional := false
 
 	for true {
-		if p.check(TokenLeftParen) {
+		if p.check(TokenQuestionDot) && p.peekNext().Type == TokenLeftParen {
+			// optional call.
+			p.advance()
+			paren = p.advance()
+			arguments = p.arguments()
+			expr = NewCall(expr, paren, arguments, true)
+			optional = true
+		} else if p.check(TokenLeftParen) {
 			p.advance()
 			paren = p.previous()
 			arguments = p.arguments()
-			expr = NewCall(expr, paren, arguments)
-		} else if p.check(TokenDot) {
+			expr = NewCall(expr, paren, arguments, false)
+		} else if p.check(TokenDot) || p.check(TokenQuestionDot) {
 			// get expression.
-			p.advance()
-			name := p.consume(TokenIdentifier, "expect a property name.")
-			expr = NewGet(expr, name)
-		} else if p.check(TokenLeftBracket) {
+			link := p.advance()
+			name := p.propertyName()
+			expr = NewGet(expr, name, link.Type == TokenQuestionDot)
+			optional = optional || link.Type == TokenQuestionDot
+		} else if p.check(TokenLeftBracket) || p.check(TokenQuestionBracket) {
 			bracket := p.advance()
 			key := p.expression()
 			p.consume(TokenRightBracket, "expect ']' after index.")
-			expr = NewSubscript(expr, key, bracket)
+			expr = NewSubscript(expr, key, bracket, bracket.Type == TokenQuestionBracket)
+			optional = optional || bracket.Type == TokenQuestionBracket
 		} else {
 			break
 		}
 	}
 
+	if optional {
+		return NewChain(expr)
+	}
 	return expr
 }
 
+// propertyName consumes the name of a property, which might be a keyword, e.g. `regex.match`.
+func (p *Parser) propertyName() *Token {
+	if name := p.peek(); name.Type != TokenIdentifier {
+		if _, ok := keywords[name.Lexeme]; ok {
+			p.advance()
+			return NewToken(TokenIdentifier, name.Lexeme, nil, name.Line)
+		}
+	}
+	return p.consume(TokenIdentifier, "expect a property name.")
+}
+
+// argument parses an argument of a call or an element of an array literal, which
+// might spread an array, e.g. `...arr`.
+func (p *Parser) argument() Expr {
+	if p.match(TokenEllipsis) {
+		ellipsis := p.previous()
+		return NewSpread(ellipsis, p.expression())
+	}
+	return p.expression()
+}
+
 func (p *Parser) arguments() []Expr {
 	exprs := make([]Expr, 0)
 
 	for !p.check(TokenRightParen) {
-		expr := p.expression()
+		expr := p.argument()
 		exprs = append(exprs, expr)
 		if p.check(TokenRightParen) {
 			break
@@ -626,27 +999,8 @@ func (p *Parser) primary() Expr {
 	case p.match(TokenNumber, TokenString):
 		return NewLiteral(p.previous().Literal)
 	case p.match(TokenLeftParen):
-		if p.check(TokenIdentifier) && p.peekNext().Type == TokenComma {
-			params := make([]*Token, 0)
-
-			for !p.check(TokenRightParen) {
-				param := p.consume(TokenIdentifier, "expect parameter to be an identifier.")
-				params = append(params, param)
-				if !p.check(TokenRightParen) {
-					p.consume(TokenComma, "params should be separated by commas.")
-					// TODO: Fix the trailing comma.
-				}
-			}
-
-			p.consume(TokenRightParen, "expect ')' after parameter list.")
-
-			return p.lambda(params)
-		} else if p.match(TokenRightParen) {
-			if p.check(TokenArrow) {
-				// an empty list of parameter.
-				return p.lambda(nil)
-			}
-			panic(NewLoxError(p.previous(), "unexpected token."))
+		if function, destructures, ok := p.lambdaParameters(); ok {
+			return p.lambda(function, destructures)
 		}
 
 		expr := p.expression()
@@ -656,7 +1010,7 @@ func (p *Parser) primary() Expr {
 		// array literal.
 		elements := make([]Expr, 0)
 		for !p.check(TokenRightBracket) {
-			expr := p.expression()
+			expr := p.argument()
 			if !p.check(TokenRightBracket) {
 				p.consume(TokenComma, "expect ',' to separate elements.")
 			}
@@ -664,21 +1018,197 @@ func (p *Parser) primary() Expr {
 		}
 		p.consume(TokenRightBracket, "expect ']' after array elements.")
 		return NewArray(elements)
+	case p.match(TokenMatch):
+		return p.matchExpr(false)
 	case p.match(TokenSuper):
 		keyword := p.previous()
 		p.consume(TokenDot, "expect '.' after 'super'.")
-		method := p.consume(TokenIdentifier, "expect superclass method name.")
+		method := p.consume(TokenIdentifier, "expect superclass property name.")
 		return NewSuper(keyword, method)
 	default:
 		panic(NewLoxError(p.peek(), "expect expression."))
 	}
 }
 
-func (p *Parser) lambda(params []*Token) Expr {
-	var (
-		body []Stmt
-		fun  *Function
-	)
+// matchExpr parses a match, whose cases run statements if it is a statement.
+func (p *Parser) matchExpr(statement bool) Expr {
+	keyword := p.previous()
+	p.consume(TokenLeftParen, "expect '(' after 'match'.")
+	subject := p.expression()
+	p.consume(TokenRightParen, "expect ')' after match value.")
+	p.consume(TokenLeftBrace, "expect '{' before match cases.")
+
+	cases := make([]*Case, 0)
+	for !p.check(TokenRightBrace) && !p.end() {
+		c := &Case{Keyword: p.consume(TokenCase, "expect 'case'.")}
+		c.Patterns = append(c.Patterns, p.pattern())
+		for p.match(TokenComma) {
+			c.Patterns = append(c.Patterns, p.pattern())
+		}
+		if p.match(TokenIf) {
+			c.Guard = p.expression()
+		}
+		p.consume(TokenArrow, "expect '->' after case patterns.")
+
+		if statement {
+			c.Body = p.statement()
+		} else {
+			c.Value = p.expression()
+			// cases might be separated by commas or semicolons.
+			if p.check(TokenComma) || p.check(TokenSemi) {
+				p.advance()
+			}
+		}
+		cases = append(cases, c)
+	}
+
+	p.consume(TokenRightBrace, "expect '}' after match cases.")
+	return NewMatch(keyword, subject, cases, statement)
+}
+
+// pattern parses a pattern of a match case.
+func (p *Parser) pattern() Pattern {
+	switch {
+	case p.match(TokenLeftBracket):
+		bracket := p.previous()
+		elements := make([]Pattern, 0)
+		var rest Pattern
+
+		for !p.check(TokenRightBracket) {
+			// the rest pattern comes last.
+			if p.match(TokenEllipsis) {
+				rest = &BindingPattern{p.consume(TokenIdentifier, "expect a name after '...'.")}
+				break
+			}
+			elements = append(elements, p.element())
+			if !p.check(TokenRightBracket) {
+				p.consume(TokenComma, "expect ',' to separate elements.")
+			}
+		}
+		p.consume(TokenRightBracket, "expect ']' after array pattern.")
+		return &ArrayPattern{bracket, elements, rest}
+	case p.match(TokenLeftBrace):
+		brace := p.previous()
+		keys := make([]*Token, 0)
+		values := make([]Pattern, 0)
+
+		for !p.check(TokenRightBrace) {
+			key := p.propertyName()
+			keys = append(keys, key)
+			if p.match(TokenColon) {
+				values = append(values, p.element())
+			} else if p.match(TokenEqual) {
+				values = append(values, &DefaultPattern{&BindingPattern{key}, p.expression()})
+			} else {
+				values = append(values, &BindingPattern{key})
+			}
+			if !p.check(TokenRightBrace) {
+				p.consume(TokenComma, "expect ',' to separate fields.")
+			}
+		}
+		p.consume(TokenRightBrace, "expect '}' after object pattern.")
+		return &ObjectPattern{brace, keys, values}
+	case p.match(TokenIdentifier):
+		name := p.previous()
+
+		if p.match(TokenLeftParen) {
+			fields := make([]Pattern, 0)
+			for !p.check(TokenRightParen) {
+				fields = append(fields, p.pattern())
+				if !p.check(TokenRightParen) {
+					p.consume(TokenComma, "expect ',' to separate fields.")
+				}
+			}
+			p.consume(TokenRightParen, "expect ')' after class pattern fields.")
+			class, _ := NewVariable(name).(*Variable)
+			return &ClassPattern{class, fields}
+		}
+
+		if p.check(TokenDot) {
+			// a constant, e.g. an enum member.
+			value := NewVariable(name)
+			for p.match(TokenDot) {
+				value = NewGet(value, p.propertyName(), false)
+			}
+			return &ValuePattern{name, value}
+		}
+		return &BindingPattern{name}
+	default:
+		token := p.peek()
+		value := p.literal()
+		if !p.match(TokenDotDot) {
+			return &ValuePattern{token, value}
+		}
+
+		dots := p.previous()
+		high := p.literal()
+		for _, bound := range []Expr{value, high} {
+			if _, ok := numberValue(bound.(*Literal).Value); !ok {
+				panic(NewLoxError(dots, "bounds of a range must be numbers."))
+			}
+		}
+		return &RangePattern{value, dots, high}
+	}
+}
+
+// element parses the element of an array or the field of an object pattern,
+// which might have a default.
+func (p *Parser) element() Pattern {
+	pattern := p.pattern()
+	if p.match(TokenEqual) {
+		return &DefaultPattern{pattern, p.expression()}
+	}
+	return pattern
+}
+
+// literal parses the literal of a pattern, numbers might be negative.
+func (p *Parser) literal() Expr {
+	switch {
+	case p.match(TokenMinus):
+		number := p.consume(TokenNumber, "expect a number after '-'.")
+		if value, ok := number.Literal.(int); ok {
+			return NewLiteral(-value)
+		}
+		value, _ := number.Literal.(float64)
+		return NewLiteral(-value)
+	case p.match(TokenNumber, TokenString):
+		return NewLiteral(p.previous().Literal)
+	case p.match(TokenTrue):
+		return NewLiteral(true)
+	case p.match(TokenFalse):
+		return NewLiteral(false)
+	case p.match(TokenNil):
+		return NewLiteral(nil)
+	}
+	panic(NewLoxError(p.peek(), "expect a pattern."))
+}
+
+// lambdaParameters parses the parameters of a lambda after "(", up to "->". It
+// rewinds & returns false if the parenthesis doesn't start a lambda, e.g. `(a + b)`.
+func (p *Parser) lambdaParameters() (function *Function, destructures []Stmt, ok bool) {
+	start := p.current
+	defer func() {
+		if val := recover(); val != nil {
+			if _, isLoxError := val.(*LoxError); !isLoxError {
+				panic(val)
+			}
+			p.current = start
+			function, destructures, ok = nil, nil, false
+		}
+	}()
+
+	function, destructures = p.parameters()
+	p.consume(TokenRightParen, "expect ')' after parameter list.")
+	if !p.check(TokenArrow) {
+		p.current = start
+		return nil, nil, false
+	}
+	return function, destructures, true
+}
+
+// lambda parses the body of a lambda, which destructures the parameters first.
+func (p *Parser) lambda(function *Function, destructures []Stmt) Expr {
+	var body []Stmt
 
 	p.consume(TokenArrow, "expect '->' after parameter list.")
 
@@ -704,6 +1234,6 @@ func (p *Parser) lambda(params []*Token) Expr {
 		body = append(body, returnStmt)
 	}
 
-	fun, _ = NewFunction(nil, params, body).(*Function)
-	return NewLambda(fun)
+	function.Body = append(destructures, body...)
+	return NewLambda(function)
 }
@@ -0,0 +1,87 @@
+package lox
+
+import "strings"
+
+// Private members are named like `#name`. They can only be accessed through
+// `this` in the methods of the class declaring them, which the resolver checks
+// statically & the interpreter enforces at runtime. Each class has its own private
+// members, i.e. a subclass neither sees nor overrides those of its superclass.
+// Private fields aren't stored in props, so they don't show up in prints, JSON or
+// subscripts.
+
+// privateKey identifies a private field of an instance.
+type privateKey struct {
+	class *LoxClass
+	name  string
+}
+
+func isPrivate(name string) bool {
+	return strings.HasPrefix(name, "#")
+}
+
+// isSubclassOf reports whether `c` is `class` or one of its subclasses.
+func (c *LoxClass) isSubclassOf(class *LoxClass) bool {
+	for super := c; super != nil; super = super.Super {
+		if super == class {
+			return true
+		}
+	}
+	return false
+}
+
+// privateClass returns the class whose private member `name` of `instance` is accessed,
+// i.e. the class of the running method. It fails if the running code isn't allowed to.
+func (i *Interpreter) privateClass(instance *LoxInstance, name *Token) *LoxClass {
+	class := i.currentClass
+	if class == nil || !instance.class.isSubclassOf(class) {
+		panic(NewRuntimeError(name, "private member '"+name.Lexeme+"' is not accessible here."))
+	}
+	return class
+}
+
+// getPrivate returns a private field, a bound private method or the value of a private getter.
+func (o *LoxInstance) getPrivate(interpreter *Interpreter, name *Token) interface{} {
+	class := interpreter.privateClass(o, name)
+
+	if val, ok := o.privates[privateKey{class, name.Lexeme}]; ok {
+		return val
+	}
+
+	if method := findFunction(class.Methods, o, name.Lexeme); method != nil {
+		// binding creates a closure.
+		interpreter.alloc(sizeEnv + sizeFunction)
+		return method
+	}
+
+	if getter := findFunction(class.Getters, o, name.Lexeme); getter != nil {
+		return getter.Call(interpreter, nil)
+	}
+
+	panic(NewRuntimeError(name, "undefined private member '"+name.Lexeme+"'."))
+}
+
+// setPrivate sets a private field, or calls a private setter.
+func (o *LoxInstance) setPrivate(interpreter *Interpreter, name *Token, value interface{}) interface{} {
+	class := interpreter.privateClass(o, name)
+
+	if setter := findFunction(class.Setters, o, name.Lexeme); setter != nil {
+		return setter.Call(interpreter, value)
+	}
+
+	key := privateKey{class, name.Lexeme}
+	if o.privates == nil {
+		o.privates = map[privateKey]interface{}{}
+	}
+	if _, ok := o.privates[key]; !ok {
+		interpreter.alloc(sizeSlot + len(name.Lexeme))
+	}
+	o.privates[key] = value
+	return value
+}
+
+// checkSubscriptKey fails if `key` names a private member, which can't be accessed by subscript.
+func checkSubscriptKey(token *Token, key string) {
+	if isPrivate(key) {
+		panic(NewRuntimeError(token, "private member '"+key+"' can't be accessed by subscript."))
+	}
+}
@@ -0,0 +1,107 @@
+package lox
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"os"
+)
+
+// LoxOS is the runtime object for the builtin os module.
+// `os.args` holds the arguments passed to the script, see Interpreter.SetArgs.
+var LoxOS *LoxClass
+
+// stdinSource reads from the stdin of the interpreter, so that input(), readAll()
+// and the stdin LineReader share the same buffer.
+type stdinSource struct {
+	interp *Interpreter
+}
+
+func (s stdinSource) ReadString(delim byte) (string, error) {
+	return s.interp.stdin.ReadString(delim)
+}
+
+// SetArgs sets `os.args` to `args`.
+func (i *Interpreter) SetArgs(args []string) {
+	list := make([]interface{}, len(args))
+	for index, arg := range args {
+		list[index] = arg
+	}
+	i.osModule.Fields["args"] = newArray(i, list)
+}
+
+// SetStdin makes scripts read their input from `reader`.
+func (i *Interpreter) SetStdin(reader io.Reader) {
+	i.stdin = bufio.NewReader(reader)
+}
+
+// Exited reports whether the script called os.exit, along with its exit code.
+func (i *Interpreter) Exited() (int, bool) {
+	if i.exitError == nil {
+		return 0, false
+	}
+	return i.exitError.Code, true
+}
+
+// init os module. This function will be called when an Interpreter is instantiated.
+func initOS() {
+	var statics = map[string]Callable{
+		// env(name) returns the value of an environment variable, or nil if it is unset.
+		"env": NewBuiltinFunc(1, func(interp *Interpreter, i *LoxInstance, args ...interface{}) interface{} {
+			value, ok := os.LookupEnv(stringArg("env", args, 0))
+			if !ok {
+				return nil
+			}
+			interp.alloc(sizeString + len(value))
+			return value
+		}),
+		// exit(code?) stops the script, the code defaults to 0.
+		"exit": NewBuiltinFunc(-1, func(interp *Interpreter, i *LoxInstance, args ...interface{}) interface{} {
+			checkArgs("exit", args, 0, 1)
+			code := 0
+			if len(args) == 1 {
+				code = intArg("exit", args, 0)
+			}
+			panic(&ExitError{code})
+		}),
+	}
+
+	LoxOS = NewLoxClass("os", nil, statics, nil, nil, nil)
+}
+
+// defineProcess defines the os module & the globals reading from stdin.
+func (i *Interpreter) defineProcess() {
+	// every interpreter has its own args.
+	i.osModule = NewLoxClass("os", nil, LoxOS.Statics, nil, nil, nil)
+	i.osModule.Fields["args"] = newArray(i, []interface{}{})
+	i.global.Define("os", i.osModule)
+
+	// input(prompt?) prints `prompt` & reads a line, it returns nil at the end of the input.
+	i.global.Define("input", NewBuiltinFunc(-1, func(interp *Interpreter, _ *LoxInstance, args ...interface{}) interface{} {
+		checkArgs("input", args, 0, 1)
+		if len(args) == 1 {
+			fmt.Print(stringArg("input", args, 0))
+		}
+
+		line, ok, err := readLine(interp, stdinSource{interp})
+		if err != nil {
+			panic(NewRuntimeError(nil, fmt.Sprintf("input(): %v.", err)))
+		}
+		if !ok {
+			return nil
+		}
+		return line
+	}))
+
+	// readAll() reads the rest of the input.
+	i.global.Define("readAll", NewBuiltinFunc(0, func(interp *Interpreter, _ *LoxInstance, args ...interface{}) interface{} {
+		content, err := io.ReadAll(interp.stdin)
+		if err != nil {
+			panic(NewRuntimeError(nil, fmt.Sprintf("readAll(): %v.", err)))
+		}
+		interp.alloc(sizeString + len(content))
+		return string(content)
+	}))
+
+	i.global.Define("stdin", newLineReader(i, stdinSource{i}, nil))
+}
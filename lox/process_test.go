@@ -0,0 +1,80 @@
+package lox
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestOSArgsAndEnv(t *testing.T) {
+	t.Setenv("GOLOX_TEST_VAR", "value")
+	interpreter := NewInterpreter(false)
+	interpreter.SetArgs([]string{"first", "second"})
+
+	src := `
+	var count = os.args.length
+	var second = os.args[1]
+	var value = os.env("GOLOX_TEST_VAR")
+	var unset = os.env("GOLOX_TEST_UNSET")
+	`
+	if runWith(t, interpreter, src) {
+		t.Fatalf("unexpected runtime error: %v", interpreter.LastError())
+	}
+
+	global := interpreter.global.values
+	if global["count"] != 2 || global["second"] != "second" {
+		t.Errorf("unexpected args %v, %v", global["count"], global["second"])
+	}
+	if global["value"] != "value" || global["unset"] != nil {
+		t.Errorf("unexpected env %v, %v", global["value"], global["unset"])
+	}
+}
+
+func TestStdin(t *testing.T) {
+	interpreter := NewInterpreter(false)
+	interpreter.SetStdin(strings.NewReader("name\r\nfirst\nsecond\nrest\nof input"))
+
+	src := `
+	var name = input()
+	// the reader is out of reach of scripts.
+	stdin.source = 1
+	stdin.closer = nil
+	var lines = []
+	for (var i = 0; i < 2; i = i + 1) {
+		lines.append(stdin.next());
+	}
+	var rest = readAll()
+	var eof = input()
+	var next = stdin.next()
+	`
+	if runWith(t, interpreter, src) {
+		t.Fatalf("unexpected runtime error: %v", interpreter.LastError())
+	}
+
+	global := interpreter.global.values
+	if global["name"] != "name" {
+		t.Errorf("unexpected input %q", global["name"])
+	}
+	if lines := arrayList(global["lines"].(*_arrayInsType).LoxInstance); len(lines) != 2 || lines[1] != "second" {
+		t.Errorf("unexpected lines %v", lines)
+	}
+	if global["rest"] != "rest\nof input" {
+		t.Errorf("unexpected rest %q", global["rest"])
+	}
+	if global["eof"] != nil || global["next"] != nil {
+		t.Error("expect nil at the end of the input.")
+	}
+}
+
+func TestOSExit(t *testing.T) {
+	interpreter := NewInterpreter(false)
+
+	if runWith(t, interpreter, "var before = 1; os.exit(3); var after = 1;") {
+		t.Fatalf("expect exit not to be a runtime error, but got %v", interpreter.LastError())
+	}
+	if code, exited := interpreter.Exited(); !exited || code != 3 {
+		t.Errorf("expect exit code 3, but got %v, %v", code, exited)
+	}
+	if _, ok := interpreter.global.values["after"]; ok {
+		t.Error("expect the script to stop at os.exit.")
+	}
+}
@@ -0,0 +1,129 @@
+package lox
+
+import (
+	"fmt"
+	"math"
+	"math/rand/v2"
+)
+
+// LoxRandom is the runtime object for the builtin Random class.
+// `Random(seed)` creates a generator whose sequence only depends on `seed`,
+// `Random()` one seeded by the clock. The statics, e.g. Random.int(1, 6),
+// use the default generator of the interpreter, which Random.seed(n) reseeds.
+var LoxRandom *LoxClass
+
+// newRand returns a generator, the algorithm (PCG) is fixed so that sequences are stable across releases.
+func newRand(seed uint64) *rand.Rand {
+	return rand.New(rand.NewPCG(seed, seed))
+}
+
+// random returns the default generator of the interpreter.
+func (i *Interpreter) random() *rand.Rand {
+	if i.rand == nil {
+		i.rand = newRand(uint64(i.clock.Now().UnixNano()))
+	}
+	return i.rand
+}
+
+func randomValue(i *LoxInstance) *rand.Rand {
+	r, ok := i.native.(*rand.Rand)
+	if !ok {
+		panic(NewRuntimeError(nil, "random generator is not initialized."))
+	}
+	return r
+}
+
+// randomFunc is implemented by both the methods & the statics of Random.
+type randomFunc struct {
+	arity int
+	call  func(interp *Interpreter, r *rand.Rand, args []interface{}) interface{}
+}
+
+var randomFuncs = map[string]randomFunc{
+	// int(lo, hi) returns an integer in [lo, hi].
+	"int": {2, func(interp *Interpreter, r *rand.Rand, args []interface{}) interface{} {
+		lo, hi := intArg("int", args, 0), intArg("int", args, 1)
+		if lo > hi {
+			panic(NewRuntimeError(nil, "int() expects lo to be less than or equal to hi."))
+		}
+		// the span might overflow an int, but not an uint64, & wraps back into [lo, hi].
+		span := uint64(hi) - uint64(lo)
+		if span == math.MaxUint64 {
+			return int(r.Uint64())
+		}
+		return lo + int(r.Uint64N(span+1))
+	}},
+	// float() returns a number in [0, 1).
+	"float": {0, func(interp *Interpreter, r *rand.Rand, args []interface{}) interface{} {
+		return r.Float64()
+	}},
+	"choice": {1, func(interp *Interpreter, r *rand.Rand, args []interface{}) interface{} {
+		list := arrayArg("choice", args, 0)
+		if len(list) == 0 {
+			panic(NewRuntimeError(nil, "choice() expects a non-empty array."))
+		}
+		return list[r.IntN(len(list))]
+	}},
+	// shuffle(array) shuffles the array in place and returns it.
+	"shuffle": {1, func(interp *Interpreter, r *rand.Rand, args []interface{}) interface{} {
+		list := arrayArg("shuffle", args, 0)
+		args[0].(*_arrayInsType).checkFrozen(nil)
+		r.Shuffle(len(list), func(a, b int) {
+			list[a], list[b] = list[b], list[a]
+		})
+		return args[0]
+	}},
+	// sample(array, k) returns k elements at distinct positions of the array, in random order.
+	"sample": {2, func(interp *Interpreter, r *rand.Rand, args []interface{}) interface{} {
+		list := arrayArg("sample", args, 0)
+		k := intArg("sample", args, 1)
+		if k < 0 || k > len(list) {
+			panic(NewRuntimeError(nil, fmt.Sprintf("sample() expects k to be in [0, %v].", len(list))))
+		}
+
+		// the first k positions of a random permutation.
+		indices := r.Perm(len(list))[:k]
+		sample := make([]interface{}, k)
+		for index, pos := range indices {
+			sample[index] = list[pos]
+		}
+		return newArray(interp, sample)
+	}},
+}
+
+// init Random class. This function will be called when an Interpreter is instantiated.
+func initRandom() {
+	var statics = map[string]Callable{
+		// seed(n) reseeds the default generator.
+		"seed": NewBuiltinFunc(1, func(interp *Interpreter, i *LoxInstance, args ...interface{}) interface{} {
+			interp.rand = newRand(uint64(intArg("seed", args, 0)))
+			return nil
+		}),
+	}
+
+	var methods = map[string]Callable{
+		// Random(seed?) creates a generator, seeded by the clock without `seed`.
+		"init": NewBuiltinFunc(-1, func(interp *Interpreter, i *LoxInstance, args ...interface{}) interface{} {
+			checkArgs("Random", args, 0, 1)
+			seed := uint64(interp.clock.Now().UnixNano())
+			if len(args) == 1 {
+				seed = uint64(intArg("Random", args, 0))
+			}
+			interp.alloc(sizeSlot)
+			i.native = newRand(seed)
+			return nil
+		}),
+	}
+
+	for name, fn := range randomFuncs {
+		fn := fn
+		statics[name] = NewBuiltinFunc(fn.arity, func(interp *Interpreter, i *LoxInstance, args ...interface{}) interface{} {
+			return fn.call(interp, interp.random(), args)
+		})
+		methods[name] = NewBuiltinFunc(fn.arity, func(interp *Interpreter, i *LoxInstance, args ...interface{}) interface{} {
+			return fn.call(interp, randomValue(i), args)
+		})
+	}
+
+	LoxRandom = NewLoxClass("Random", nil, statics, methods, nil, nil)
+}
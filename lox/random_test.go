@@ -0,0 +1,39 @@
+package lox
+
+import "testing"
+
+// The sequences are pinned, a seed must produce the same values across runs & releases.
+func TestRandomSeeded(t *testing.T) {
+	src := `
+	var r = Random(42)
+	var ints = [r.int(1, 100), r.int(1, 100), r.int(1, 100), r.int(-5, 5)].join()
+	var f = r.float()
+	var c = r.choice(["a", "b", "c", "d"])
+	var s = r.shuffle([1, 2, 3, 4, 5]).join()
+	var k = r.sample([1, 2, 3, 4, 5], 3).join()
+	`
+	runResult(t, src, "ints", "62,38,64,0")
+	runResult(t, src, "f", 0.26685010215417193)
+	runResult(t, src, "c", "b")
+	runResult(t, src, "s", "1,5,3,4,2")
+	runResult(t, src, "k", "3,5,4")
+}
+
+func TestRandomDefault(t *testing.T) {
+	runResult(t, "Random.seed(7);", "[Random.int(0, 9), Random.int(0, 9), Random.int(0, 9)].join()", "9,0,7")
+	runResult(t, "", "Random.int(3, 3)", 3)
+	runResult(t, "", "Random.sample([1, 2], 0).length", 0)
+	// spans wider than the largest integer.
+	bounds := "var max = 4611686018427387904 * 2 - 1; var min = -4611686018427387904 * 2;"
+	runResult(t, bounds, "Random.int(-1, max) >= -1", true)
+	runResult(t, bounds, "Random(1).int(min, max) <= max", true)
+	runRuntimeErrStmt(t, "Random.int(2, 1);")
+	runRuntimeErrStmt(t, "Random(1).choice([]);")
+	runRuntimeErrStmt(t, "Random.sample([1], 2);")
+}
+
+func TestRandomHidesGenerator(t *testing.T) {
+	runResult(t, "var r = Random(1);", "Reflect.fields(r).length", 0)
+	runResult(t, "var r = Random(1); r.rand = 5;", "r.int(3, 3)", 3)
+	runRuntimeErrStmt(t, "Random(1)[\"rand\"];")
+}
@@ -0,0 +1,224 @@
+package lox
+
+import (
+	"fmt"
+	"sort"
+)
+
+// LoxReflect is the runtime object for the builtin Reflect module, which
+// enumerates the fields of instances, the members of classes & traits, and the
+// signatures of functions. Names are returned sorted, private members are hidden.
+var LoxReflect *LoxClass
+
+// instanceArg returns args[index] as an instance for native function `name`.
+func instanceArg(name string, args []interface{}, index int) *LoxInstance {
+	instance, ok := args[index].(*LoxInstance)
+	if !ok {
+		panic(NewRuntimeError(nil, fmt.Sprintf("%v() expects argument %v to be an instance.", name, index+1)))
+	}
+	return instance
+}
+
+// fieldArg returns args[index] as the name of a public field for native function `name`.
+func fieldArg(name string, args []interface{}, index int) string {
+	field := stringArg(name, args, index)
+	if isPrivate(field) {
+		panic(NewRuntimeError(nil, name+"() can't access private member '"+field+"'."))
+	}
+	return field
+}
+
+// sortedNames returns an array of the public names in `table`, sorted.
+func sortedNames(interp *Interpreter, table map[string]Callable) *_arrayInsType {
+	names := make([]string, 0, len(table))
+	for name := range table {
+		if !isPrivate(name) {
+			names = append(names, name)
+		}
+	}
+	sort.Strings(names)
+
+	list := make([]interface{}, len(names))
+	for index, name := range names {
+		interp.alloc(sizeString + len(name))
+		list[index] = name
+	}
+	return newArray(interp, list)
+}
+
+// memberTable returns the methods, getters or setters of a class or a trait, depending on `kind`.
+func memberTable(name string, value interface{}, kind int) map[string]Callable {
+	switch val := value.(type) {
+	case *LoxClass:
+		return []map[string]Callable{val.Methods, val.Getters, val.Setters}[kind]
+	case *LoxTrait:
+		table := map[string]Callable{}
+		for _, fn := range [][]*Function{val.Declaration.Methods, val.Declaration.Getters, val.Declaration.Setters}[kind] {
+			table[fn.Name.Lexeme] = nil
+		}
+		return table
+	}
+	panic(NewRuntimeError(nil, name+"() expects a class or a trait."))
+}
+
+// nameBuiltins names the builtin functions of `table` after their keys.
+func nameBuiltins(table map[string]Callable) {
+	for name, fn := range table {
+		if builtin, ok := fn.(*BuiltInFunc); ok && builtin.name == "" {
+			builtin.name = name
+		}
+	}
+}
+
+// init Reflect module. This function will be called when an Interpreter is instantiated.
+func initReflect() {
+	var statics = map[string]Callable{
+		// fields(obj) returns the names of the fields of an instance.
+		"fields": NewBuiltinFunc(1, func(interp *Interpreter, i *LoxInstance, args ...interface{}) interface{} {
+			table := map[string]Callable{}
+			for name := range instanceArg("fields", args, 0).props {
+				table[name] = nil
+			}
+			return sortedNames(interp, table)
+		}),
+		"hasField": NewBuiltinFunc(2, func(interp *Interpreter, i *LoxInstance, args ...interface{}) interface{} {
+			_, ok := instanceArg("hasField", args, 0).props[fieldArg("hasField", args, 1)]
+			return ok
+		}),
+		// getField(obj, name) returns a field, or nil. Methods & getters are not fields.
+		"getField": NewBuiltinFunc(2, func(interp *Interpreter, i *LoxInstance, args ...interface{}) interface{} {
+			return instanceArg("getField", args, 0).props[fieldArg("getField", args, 1)]
+		}),
+		// setField(obj, name, value) sets a field without calling setters.
+		"setField": NewBuiltinFunc(3, func(interp *Interpreter, i *LoxInstance, args ...interface{}) interface{} {
+			instance := instanceArg("setField", args, 0)
+			name := fieldArg("setField", args, 1)
+			instance.checkFrozen(nil)
+			if _, ok := instance.props[name]; !ok {
+				interp.alloc(sizeSlot + len(name))
+			}
+			instance.props[name] = args[2]
+			return args[2]
+		}),
+		// deleteField(obj, name) deletes a field & reports whether it existed.
+		"deleteField": NewBuiltinFunc(2, func(interp *Interpreter, i *LoxInstance, args ...interface{}) interface{} {
+			instance := instanceArg("deleteField", args, 0)
+			name := fieldArg("deleteField", args, 1)
+			instance.checkFrozen(nil)
+			_, ok := instance.props[name]
+			delete(instance.props, name)
+			return ok
+		}),
+
+		// methods(cls), getters(cls) & setters(cls) return the names of the members
+		// declared by a class or a trait, not the inherited ones.
+		"methods": NewBuiltinFunc(1, func(interp *Interpreter, i *LoxInstance, args ...interface{}) interface{} {
+			return sortedNames(interp, memberTable("methods", args[0], 0))
+		}),
+		"getters": NewBuiltinFunc(1, func(interp *Interpreter, i *LoxInstance, args ...interface{}) interface{} {
+			return sortedNames(interp, memberTable("getters", args[0], 1))
+		}),
+		"setters": NewBuiltinFunc(1, func(interp *Interpreter, i *LoxInstance, args ...interface{}) interface{} {
+			return sortedNames(interp, memberTable("setters", args[0], 2))
+		}),
+		// statics(cls) returns the names of the static methods, fields, getters &
+		// setters declared by a class.
+		"statics": NewBuiltinFunc(1, func(interp *Interpreter, i *LoxInstance, args ...interface{}) interface{} {
+			class, ok := args[0].(*LoxClass)
+			if !ok {
+				panic(NewRuntimeError(nil, "statics() expects a class."))
+			}
+
+			table := map[string]Callable{}
+			for _, members := range []map[string]Callable{class.Statics, class.StaticGetters, class.StaticSetters} {
+				for name, fn := range members {
+					table[name] = fn
+				}
+			}
+			for name := range class.Fields {
+				table[name] = nil
+			}
+			return sortedNames(interp, table)
+		}),
+		"superclass": NewBuiltinFunc(1, func(interp *Interpreter, i *LoxInstance, args ...interface{}) interface{} {
+			class, ok := args[0].(*LoxClass)
+			if !ok {
+				panic(NewRuntimeError(nil, "superclass() expects a class."))
+			}
+			if class.Super == nil {
+				return nil
+			}
+			return class.Super
+		}),
+		// traits(cls) returns the traits composed by a class, in order.
+		"traits": NewBuiltinFunc(1, func(interp *Interpreter, i *LoxInstance, args ...interface{}) interface{} {
+			class, ok := args[0].(*LoxClass)
+			if !ok {
+				panic(NewRuntimeError(nil, "traits() expects a class."))
+			}
+			list := make([]interface{}, len(class.Traits))
+			for index, trait := range class.Traits {
+				list[index] = trait
+			}
+			return newArray(interp, list)
+		}),
+
+		// name(value) returns the name of a function, a class, a trait or an interface.
+		// It is nil for lambdas.
+		"name": NewBuiltinFunc(1, func(interp *Interpreter, i *LoxInstance, args ...interface{}) interface{} {
+			switch val := args[0].(type) {
+			case *LoxFunction:
+				if val.Declaration.Name == nil {
+					return nil
+				}
+				return val.Declaration.Name.Lexeme
+			case *BuiltInFunc:
+				return val.name
+			case *LoxClass:
+				return val.Name
+			case *LoxTrait:
+				return val.Name
+			case *LoxInterface:
+				return val.Name
+			}
+			panic(NewRuntimeError(nil, "name() expects a function, a class, a trait or an interface."))
+		}),
+		// arity(fn) returns the number of arguments a function takes at most, -1 if it is variadic.
+		"arity": NewBuiltinFunc(1, func(interp *Interpreter, i *LoxInstance, args ...interface{}) interface{} {
+			return callableArg("arity", args, 0).Arity()
+		}),
+		// params(fn) returns the names of the parameters of a function, the rest
+		// parameter is prefixed by "...". Builtin functions have no names for them,
+		// so they are named `arg1`, `arg2`...
+		"params": NewBuiltinFunc(1, func(interp *Interpreter, i *LoxInstance, args ...interface{}) interface{} {
+			var params []string
+			switch val := args[0].(type) {
+			case *LoxFunction:
+				for _, param := range val.Declaration.Params {
+					params = append(params, param.Lexeme)
+				}
+				if rest := val.Declaration.Rest; rest != nil {
+					params = append(params, "..."+rest.Lexeme)
+				}
+			case *BuiltInFunc:
+				if val.arity == -1 {
+					params = append(params, "...args")
+				}
+				for index := 0; index < val.arity; index++ {
+					params = append(params, fmt.Sprintf("arg%v", index+1))
+				}
+			default:
+				panic(NewRuntimeError(nil, "params() expects a function."))
+			}
+
+			list := make([]interface{}, len(params))
+			for index, param := range params {
+				interp.alloc(sizeString + len(param))
+				list[index] = param
+			}
+			return newArray(interp, list)
+		}),
+	}
+
+	LoxReflect = NewLoxClass("Reflect", nil, statics, nil, nil, nil)
+}
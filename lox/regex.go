@@ -0,0 +1,129 @@
+package lox
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+)
+
+// LoxRegex is the runtime object for the builtin Regex class, backed by go's regexp.
+// The syntax is RE2, see https://golang.org/s/re2syntax. Offsets are counted in bytes.
+var LoxRegex *LoxClass
+
+func regexValue(i *LoxInstance) *regexp.Regexp {
+	re, ok := i.native.(*regexp.Regexp)
+	if !ok {
+		panic(NewRuntimeError(nil, "regex is not initialized."))
+	}
+	return re
+}
+
+// submatches converts the submatch indices of `s` into an Array of strings,
+// unmatched groups are nil.
+func submatches(interp *Interpreter, s string, indices []int) *_arrayInsType {
+	list := make([]interface{}, len(indices)/2)
+	for index := range list {
+		start, end := indices[2*index], indices[2*index+1]
+		if start >= 0 {
+			interp.alloc(sizeString + end - start)
+			list[index] = s[start:end]
+		}
+	}
+	return newArray(interp, list)
+}
+
+// replaceFunc calls `callback` with the match, an Array of its groups & its offset,
+// and replaces the match with the returned string.
+func replaceFunc(interp *Interpreter, re *regexp.Regexp, s string, callback Callable) string {
+	var builder strings.Builder
+	last := 0
+	for _, indices := range re.FindAllStringSubmatchIndex(s, -1) {
+		builder.WriteString(s[last:indices[0]])
+
+		groups := submatches(interp, s, indices[2:])
+		replacement, ok := interp.invoke(callback, s[indices[0]:indices[1]], groups, indices[0]).(string)
+		if !ok {
+			panic(NewRuntimeError(nil, "replace() expects the callback to return a string."))
+		}
+		builder.WriteString(replacement)
+		last = indices[1]
+	}
+	builder.WriteString(s[last:])
+	return builder.String()
+}
+
+// init Regex class. This function will be called when an Interpreter is instantiated.
+func initRegex() {
+	var methods = map[string]Callable{
+		"init": NewBuiltinFunc(1, func(interp *Interpreter, i *LoxInstance, args ...interface{}) interface{} {
+			re, err := regexp.Compile(stringArg("Regex", args, 0))
+			if err != nil {
+				panic(NewRuntimeError(nil, fmt.Sprintf("Regex(): invalid pattern: %v.", err)))
+			}
+			interp.alloc(sizeSlot + len(re.String()))
+			i.native = re
+			return nil
+		}),
+		"test": NewBuiltinFunc(1, func(interp *Interpreter, i *LoxInstance, args ...interface{}) interface{} {
+			return regexValue(i).MatchString(stringArg("test", args, 0))
+		}),
+		// match(s) returns the first match & its groups, or nil if there's none.
+		"match": NewBuiltinFunc(1, func(interp *Interpreter, i *LoxInstance, args ...interface{}) interface{} {
+			s := stringArg("match", args, 0)
+			indices := regexValue(i).FindStringSubmatchIndex(s)
+			if indices == nil {
+				return nil
+			}
+			return submatches(interp, s, indices)
+		}),
+		// matchAll(s) returns an Array of all the matches, each of which is like the result of match.
+		"matchAll": NewBuiltinFunc(1, func(interp *Interpreter, i *LoxInstance, args ...interface{}) interface{} {
+			s := stringArg("matchAll", args, 0)
+			all := regexValue(i).FindAllStringSubmatchIndex(s, -1)
+			list := make([]interface{}, len(all))
+			for index, indices := range all {
+				list[index] = submatches(interp, s, indices)
+			}
+			return newArray(interp, list)
+		}),
+		// replace(s, replacement) replaces all the matches. `replacement` is either a string,
+		// in which $1 or ${name} stands for a group, or a function taking (match, groups, offset).
+		"replace": NewBuiltinFunc(2, func(interp *Interpreter, i *LoxInstance, args ...interface{}) interface{} {
+			re := regexValue(i)
+			s := stringArg("replace", args, 0)
+
+			var replaced string
+			if replacement, ok := args[1].(string); ok {
+				replaced = re.ReplaceAllString(s, replacement)
+			} else {
+				replaced = replaceFunc(interp, re, s, callableArg("replace", args, 1))
+			}
+			interp.alloc(sizeString + len(replaced))
+			return replaced
+		}),
+		// split(s, limit?) splits `s` around the matches, into at most `limit` substrings.
+		"split": NewBuiltinFunc(-1, func(interp *Interpreter, i *LoxInstance, args ...interface{}) interface{} {
+			checkArgs("split", args, 1, 2)
+			limit := -1
+			if len(args) == 2 {
+				limit = intArg("split", args, 1)
+			}
+
+			parts := regexValue(i).Split(stringArg("split", args, 0), limit)
+			list := make([]interface{}, len(parts))
+			for index, part := range parts {
+				interp.alloc(sizeString + len(part))
+				list[index] = part
+			}
+			return newArray(interp, list)
+		}),
+	}
+
+	var getters = map[string]Callable{
+		"source": NewBuiltinFunc(0, func(interp *Interpreter, i *LoxInstance, args ...interface{}) interface{} {
+			return regexValue(i).String()
+		}),
+	}
+
+	LoxRegex = NewLoxClass("Regex", nil, nil, methods, getters, nil)
+}
@@ -15,6 +15,12 @@ const (
 	FuncSetter
 )
 
+// privateScope tracks the private members of a class being resolved.
+type privateScope struct {
+	declared map[string]bool // private methods, getters, setters & assigned fields.
+	used     []*Token        // private members read.
+}
+
 // Resolver resolves bindings.
 type Resolver struct {
 	scopes      *Scopes
@@ -24,6 +30,11 @@ type Resolver struct {
 	inClass     bool
 	inSubClass  bool
 	inInit      bool
+	inStatic    bool
+	privates    []*privateScope // of the enclosing classes, nil for traits.
+	decls       map[string]Stmt // global classes, traits & interfaces known statically.
+	consts      map[string]bool // global constants declared before, the others are checked at runtime.
+	warnings    []string
 	hadError    bool
 }
 
@@ -37,6 +48,8 @@ func NewResolver(interpreter *Interpreter) *Resolver {
 		inClass:     false,
 		inSubClass:  false,
 		inInit:      false,
+		inStatic:    false,
+		decls:       map[string]Stmt{},
 		hadError:    false,
 	}
 }
@@ -53,14 +66,19 @@ func (r *Resolver) EndScope() {
 
 // Declare marks a variable is being declared yet available for used in current scope.
 func (r *Resolver) Declare(name *Token) {
+	if isPrivate(name.Lexeme) {
+		panic(NewLoxError(name, "private names are only allowed for class members."))
+	}
+
 	// don't check global scope.
 	if r.scopes.Empty() {
+		delete(r.decls, name.Lexeme)
 		return
 	}
 
 	scope := r.scopes.Peek()
 
-	if exists := scope[name.Lexeme]; exists == varDeclared || exists == varDefined {
+	if scope.HasName(name.Lexeme) {
 		panic(NewLoxError(name, "variable redeclared."))
 	}
 
@@ -105,6 +123,13 @@ func (r *Resolver) resolveStmts(stmts []Stmt) {
 	}
 }
 
+// warn reports a problem which doesn't prevent the program from running.
+func (r *Resolver) warn(token *Token, message string) {
+	warning := fmt.Sprintf("[line %v] Warning at %v: %v\n", token.Line, token.Lexeme, message)
+	r.warnings = append(r.warnings, warning)
+	fmt.Println(warning)
+}
+
 // Resolve resolves names referenced in `stmts`.
 func (r *Resolver) Resolve(stmts []Stmt) bool {
 	r.resolveStmts(stmts)
@@ -133,12 +158,69 @@ func (r *Resolver) VisitArrayExpr(expr *Array) interface{} {
 }
 
 func (r *Resolver) VisitAssignExpr(expr *Assign) interface{} {
+	if isPrivate(expr.Name.Lexeme) {
+		panic(NewLoxError(expr.Name, "private names are only allowed for class members."))
+	}
 	// check the operator at runtime.
 	r.resolve(expr.Value)
+	r.checkConst(expr.Name)
 	r.resolveLocal(expr, expr.Name)
+	if r.declaration(expr.Name) != nil {
+		delete(r.decls, expr.Name.Lexeme)
+	}
 	return nil
 }
 
+// checkConst reports assignments to local constants & to the global constants declared
+// before, those to the other global constants are reported at runtime.
+func (r *Resolver) checkConst(name *Token) {
+	for i := r.scopes.Len() - 1; i >= 0; i-- {
+		if status := r.scopes.Get(i)[name.Lexeme]; status != varUndeclared {
+			if status == varConst {
+				panic(NewLoxError(name, "cannot assign to constant '"+name.Lexeme+"'."))
+			}
+			return
+		}
+	}
+	if r.consts[name.Lexeme] {
+		panic(NewLoxError(name, "cannot assign to constant '"+name.Lexeme+"'."))
+	}
+}
+
+// defineConst records whether the variable `name` is a constant.
+func (r *Resolver) defineConst(name *Token, constant bool) {
+	if !r.scopes.Empty() {
+		if constant {
+			r.scopes.Peek()[name.Lexeme] = varConst
+		}
+		return
+	}
+
+	if r.consts == nil {
+		r.consts = map[string]bool{}
+	}
+	// the REPL might redefine a constant as a variable.
+	r.consts[name.Lexeme] = constant
+}
+
+// declare records the declaration of a global class, trait or interface.
+func (r *Resolver) declare(name *Token, stmt Stmt) {
+	if r.scopes.Empty() {
+		r.decls[name.Lexeme] = stmt
+	}
+}
+
+// declaration returns the global class, trait or interface `name` refers to, or
+// nil if it isn't known statically.
+func (r *Resolver) declaration(name *Token) Stmt {
+	for i := 0; i < r.scopes.Len(); i++ {
+		if r.scopes.Get(i).HasName(name.Lexeme) {
+			return nil
+		}
+	}
+	return r.decls[name.Lexeme]
+}
+
 func (r *Resolver) VisitBinaryExpr(expr *Binary) interface{} {
 	r.resolve(expr.Left)
 	r.resolve(expr.Right)
@@ -156,6 +238,45 @@ func (r *Resolver) VisitCallExpr(expr *Call) interface{} {
 
 func (r *Resolver) VisitGetExpr(expr *Get) interface{} {
 	r.resolve(expr.Object)
+	if isPrivate(expr.Name.Lexeme) {
+		scope := r.checkPrivate(expr.Object, expr.Name)
+		scope.used = append(scope.used, expr.Name)
+	}
+	return nil
+}
+
+// checkPrivate checks the access to a private member of `object`, and returns the scope of the class.
+func (r *Resolver) checkPrivate(object Expr, name *Token) *privateScope {
+	if len(r.privates) == 0 {
+		panic(NewLoxError(name, "private member '"+name.Lexeme+"' accessed outside of a class."))
+	}
+	if r.privates[len(r.privates)-1] == nil {
+		panic(NewLoxError(name, "private member '"+name.Lexeme+"' accessed in a trait."))
+	}
+	if _, ok := object.(*This); !ok {
+		panic(NewLoxError(name, "private member '"+name.Lexeme+"' can only be accessed through 'this'."))
+	}
+	return r.privates[len(r.privates)-1]
+}
+
+// checkPrivateKey reports subscripts with literal keys naming private members.
+func (r *Resolver) checkPrivateKey(key Expr, token *Token) {
+	if literal, ok := key.(*Literal); ok {
+		if name, ok := literal.Value.(string); ok && isPrivate(name) {
+			panic(NewLoxError(token, "private member '"+name+"' can't be accessed by subscript."))
+		}
+	}
+}
+
+func (r *Resolver) VisitChainExpr(expr *Chain) interface{} {
+	r.resolve(expr.Expression)
+	return nil
+}
+
+func (r *Resolver) VisitConditionalExpr(expr *Conditional) interface{} {
+	r.resolve(expr.Condition)
+	r.resolve(expr.ThenBranch)
+	r.resolve(expr.ElseBranch)
 	return nil
 }
 
@@ -174,6 +295,128 @@ func (r *Resolver) VisitLiteralExpr(expr *Literal) interface{} {
 	return nil
 }
 
+// VisitMatchExpr resolves the cases of a match, each in a scope binding the names
+// of its patterns. Cases which can't match are reported.
+func (r *Resolver) VisitMatchExpr(expr *Match) interface{} {
+	r.resolve(expr.Subject)
+
+	// literals & irrefutable patterns of the cases without guards.
+	matched := map[interface{}]bool{}
+	catchAll := false
+
+	for _, c := range expr.Cases {
+		if catchAll || coveredLiterals(c, matched) {
+			r.warn(c.Keyword, "unreachable case.")
+		}
+
+		r.BeginScope()
+		for _, pattern := range c.Patterns {
+			r.resolvePattern(pattern)
+		}
+		if len(c.Patterns) > 1 && len(r.scopes.Peek()) > 0 {
+			panic(NewLoxError(c.Keyword, "a case of several patterns can't bind names."))
+		}
+		if c.Guard != nil {
+			r.resolve(c.Guard)
+		}
+		if c.Body != nil {
+			r.resolve(c.Body)
+		} else {
+			r.resolve(c.Value)
+		}
+		r.EndScope()
+
+		if c.Guard != nil {
+			continue
+		}
+		for _, pattern := range c.Patterns {
+			switch pattern := pattern.(type) {
+			case *BindingPattern:
+				catchAll = true
+			case *ValuePattern:
+				if literal, ok := pattern.Value.(*Literal); ok {
+					matched[literal.Value] = true
+				}
+			}
+		}
+	}
+	return nil
+}
+
+// coveredLiterals reports whether the patterns of `c` are literals matched by previous cases.
+func coveredLiterals(c *Case, matched map[interface{}]bool) bool {
+	for _, pattern := range c.Patterns {
+		value, ok := pattern.(*ValuePattern)
+		if !ok {
+			return false
+		}
+		literal, ok := value.Value.(*Literal)
+		if !ok || !matched[literal.Value] {
+			return false
+		}
+	}
+	return true
+}
+
+// resolvePattern resolves the expressions of a pattern & declares the names it binds.
+func (r *Resolver) resolvePattern(pattern Pattern) {
+	switch pattern := pattern.(type) {
+	case *ValuePattern:
+		r.resolve(pattern.Value)
+	case *RangePattern:
+		r.resolve(pattern.Low)
+		r.resolve(pattern.High)
+	case *BindingPattern:
+		r.bind(pattern.Name)
+	case *ClassPattern:
+		r.resolve(pattern.Class)
+		for _, field := range pattern.Fields {
+			r.resolvePattern(field)
+		}
+	case *ArrayPattern:
+		for _, element := range pattern.Elements {
+			r.resolvePattern(element)
+		}
+		if pattern.Rest != nil {
+			r.resolvePattern(pattern.Rest)
+		}
+	case *ObjectPattern:
+		for _, value := range pattern.Values {
+			r.resolvePattern(value)
+		}
+	case *DefaultPattern:
+		r.resolve(pattern.Default)
+		r.resolvePattern(pattern.Pattern)
+	case *VariablePattern:
+		name := pattern.Variable.Name
+		r.checkConst(name)
+		r.resolveLocal(pattern.Variable, name)
+		if r.declaration(name) != nil {
+			delete(r.decls, name.Lexeme)
+		}
+	}
+}
+
+// bind declares & defines a name bound by a pattern, unless it is `_`.
+func (r *Resolver) bind(name *Token) {
+	if !wildcard(name) {
+		r.Declare(name)
+		r.Define(name)
+	}
+}
+
+// VisitDestructureExpr resolves the variables assigned by the pattern.
+func (r *Resolver) VisitDestructureExpr(expr *Destructure) interface{} {
+	r.resolve(expr.Value)
+	r.resolvePattern(expr.Pattern)
+	return nil
+}
+
+func (r *Resolver) VisitSpreadExpr(expr *Spread) interface{} {
+	r.resolve(expr.Expression)
+	return nil
+}
+
 // VisitLogicalExpr resolves Left & Right.
 func (r *Resolver) VisitLogicalExpr(expr *Logical) interface{} {
 	r.resolve(expr.Left)
@@ -185,12 +428,21 @@ func (r *Resolver) VisitLogicalExpr(expr *Logical) interface{} {
 func (r *Resolver) VisitSetExpr(expr *Set) interface{} {
 	r.resolve(expr.Value)
 	r.resolve(expr.Object)
+	// subscript assignments keep the key in the fake token.
+	if key, ok := expr.Name.Literal.(Expr); ok && expr.Name.Type == -1 {
+		r.resolve(key)
+		r.checkPrivateKey(key, expr.Name)
+	} else if isPrivate(expr.Name.Lexeme) {
+		scope := r.checkPrivate(expr.Object, expr.Name)
+		scope.declared[expr.Name.Lexeme] = true
+	}
 	return nil
 }
 
 func (r *Resolver) VisitSubscriptExpr(expr *Subscript) interface{} {
 	r.resolve(expr.Object)
 	r.resolve(expr.Key)
+	r.checkPrivateKey(expr.Key, expr.Bracket)
 	return nil
 }
 
@@ -204,11 +456,25 @@ func (r *Resolver) VisitSuperExpr(expr *Super) interface{} {
 	return nil
 }
 
+// VisitSuperSetExpr binds super keyword, setters are called on "this".
+func (r *Resolver) VisitSuperSetExpr(expr *SuperSet) interface{} {
+	if r.inSubClass != true || r.inClass != true {
+		panic(NewLoxError(expr.Keyword, "invalid use of 'super'."))
+	}
+
+	r.resolve(expr.Value)
+	r.resolveLocal(expr, expr.Keyword)
+	return nil
+}
+
 // VisitThisExpr binds "this"
 func (r *Resolver) VisitThisExpr(expr *This) interface{} {
 	if !r.inClass {
 		panic(NewLoxError(expr.Keyword, "\"this\" in non-class function."))
 	}
+	if r.inStatic {
+		panic(NewLoxError(expr.Keyword, "\"this\" in static method."))
+	}
 	r.resolveLocal(expr, expr.Keyword)
 	return nil
 }
@@ -221,6 +487,9 @@ func (r *Resolver) VisitUnaryExpr(expr *Unary) interface{} {
 
 // VisitVariableExpr makes sure a variable is not referenced during being declared.
 func (r *Resolver) VisitVariableExpr(expr *Variable) interface{} {
+	if isPrivate(expr.Name.Lexeme) {
+		panic(NewLoxError(expr.Name, "private names are only allowed for class members."))
+	}
 	if !r.scopes.Empty() && r.scopes.Peek()[expr.Name.Lexeme] == varDeclared {
 		panic(NewLoxError(expr.Name, "cannot read variable being declared."))
 	}
@@ -240,36 +509,65 @@ func (r *Resolver) VisitBlockStmt(stmt *Block) interface{} {
 func (r *Resolver) VisitClassStmt(stmt *Class) interface{} {
 	inClass := r.inClass
 	inSubClass := r.inSubClass
+	inStatic := r.inStatic
 
 	defer func() {
 		r.inClass = inClass
 		r.inSubClass = inSubClass
+		r.inStatic = inStatic
 	}()
 
 	r.inClass = true
+	r.inSubClass = false
 	r.Declare(stmt.Name)
+	r.checkStaticNames(stmt)
+
+	scope := &privateScope{declared: map[string]bool{}}
+	for _, functions := range [][]*Function{stmt.Methods, stmt.Getters, stmt.Setters} {
+		for _, f := range functions {
+			if isPrivate(f.Name.Lexeme) {
+				scope.declared[f.Name.Lexeme] = true
+			}
+		}
+	}
+	r.privates = append(r.privates, scope)
 
 	if stmt.Super != nil {
 		r.inSubClass = true
 		r.resolve(stmt.Super)
 	}
+	r.resolveTraits(stmt)
+	for _, name := range stmt.Interfaces {
+		r.resolve(name)
+	}
 
 	r.Define(stmt.Name)
+	r.declare(stmt.Name, stmt)
+	r.checkInterfaces(stmt)
 
-	if stmt.Super != nil {
+	if stmt.Super != nil || len(stmt.Traits) > 0 {
 		// add another scope for storing "super".
 		r.BeginScope()
 		r.scopes.Peek()["super"] = varDefined
 	}
 
-	// Since we added "this", we need another layer between the scope containing the class
-	// and the method scope.
-	r.BeginScope()
-	// before "this" is defined.
+	// static methods are not bound, they are resolved outside the scope of "this".
+	r.inStatic = true
 	for _, f := range stmt.Statics {
 		r.resolveFunction(f, FuncMeth)
 	}
+	for _, g := range stmt.StaticGetters {
+		r.resolveFunction(g, FuncGetter)
+	}
+	for _, s := range stmt.StaticSetters {
+		r.resolveFunction(s, FuncSetter)
+	}
+	r.resolveStaticInitializers(stmt.Initializers)
+	r.inStatic = false
 
+	// Since we added "this", we need another layer between the scope containing the class
+	// and the method scope.
+	r.BeginScope()
 	r.scopes.Peek()["this"] = varDefined
 
 	for _, f := range stmt.Methods {
@@ -290,13 +588,297 @@ func (r *Resolver) VisitClassStmt(stmt *Class) interface{} {
 
 	r.EndScope()
 
-	if stmt.Super != nil {
+	if stmt.Super != nil || len(stmt.Traits) > 0 {
 		r.EndScope()
 	}
 
+	r.privates = r.privates[:len(r.privates)-1]
+	for _, name := range scope.used {
+		if !scope.declared[name.Lexeme] {
+			panic(NewLoxError(name, "undefined private member '"+name.Lexeme+"'."))
+		}
+	}
+	return nil
+}
+
+// resolveTraits resolves the traits composed by a class, & reports the conflicts
+// between the traits declared by trait statements.
+func (r *Resolver) resolveTraits(stmt *Class) {
+	traits := make([]*Trait, 0, len(stmt.Traits))
+	for _, name := range stmt.Traits {
+		r.resolve(name)
+		if trait, ok := r.declaration(name.Name).(*Trait); ok {
+			traits = append(traits, trait)
+		}
+	}
+	if message := traitConflict(stmt, traits); message != "" {
+		panic(NewLoxError(stmt.Name, message))
+	}
+
+	if len(stmt.Traits) > 0 {
+		// "super" may refer to the members of the traits.
+		r.inSubClass = true
+	}
+}
+
+// checkInterfaces reports the members missing from a class for implementing its
+// interfaces, if the interfaces & the members of the class are known statically.
+func (r *Resolver) checkInterfaces(stmt *Class) {
+	members, ok := r.classMembers(stmt)
+	if !ok {
+		return
+	}
+
+	for _, name := range stmt.Interfaces {
+		iface, ok := r.declaration(name.Name).(*Interface)
+		if !ok {
+			continue
+		}
+		if message := unimplemented(iface, stmt.Name.Lexeme, members); message != "" {
+			panic(NewLoxError(stmt.Name, message))
+		}
+	}
+}
+
+// classMembers returns the lookup of the methods & getters declared, composed or
+// inherited by a class. It returns false if some of them aren't known statically.
+func (r *Resolver) classMembers(stmt *Class) (memberLookup, bool) {
+	methods := map[string]*Function{}
+	getters := map[string]bool{}
+	add := func(fns, gets []*Function) {
+		for _, method := range fns {
+			if _, ok := methods[method.Name.Lexeme]; !ok {
+				methods[method.Name.Lexeme] = method
+			}
+		}
+		for _, getter := range gets {
+			getters[getter.Name.Lexeme] = true
+		}
+	}
+
+	for class := stmt; class != nil; {
+		add(class.Methods, class.Getters)
+		for _, name := range class.Traits {
+			trait, ok := r.declaration(name.Name).(*Trait)
+			if !ok {
+				return memberLookup{}, false
+			}
+			add(trait.Methods, trait.Getters)
+		}
+
+		if class.Super == nil {
+			break
+		}
+		super, ok := r.declaration(class.Super.Name).(*Class)
+		if !ok || super == class {
+			return memberLookup{}, false
+		}
+		class = super
+	}
+
+	return memberLookup{
+		method: func(name string) (int, int, bool) {
+			method, ok := methods[name]
+			if !ok {
+				return 0, 0, false
+			}
+			min, max := method.arity()
+			return min, max, true
+		},
+		getter: func(name string) bool {
+			return getters[name]
+		},
+	}, true
+}
+
+// VisitEnumStmt resolves an enum as the class it is declared as.
+func (r *Resolver) VisitEnumStmt(stmt *Enum) interface{} {
+	members := map[string]bool{}
+	for _, member := range stmt.Members {
+		if members[member.Lexeme] {
+			panic(NewLoxError(member, "enum member '"+member.Lexeme+"' is declared more than once."))
+		}
+		if isPrivate(member.Lexeme) {
+			panic(NewLoxError(member, "enum members can't be private."))
+		}
+		members[member.Lexeme] = true
+	}
+	for _, setter := range stmt.Setters {
+		panic(NewLoxError(setter.Name, "enum members are frozen, enums can't have setters."))
+	}
+
+	for _, functions := range [][]*Function{stmt.Methods, stmt.Getters, stmt.Setters} {
+		for _, f := range functions {
+			switch f.Name.Lexeme {
+			case "init":
+				panic(NewLoxError(f.Name, "an enum cannot have an initializer."))
+			case "name", "ordinal":
+				panic(NewLoxError(f.Name, "'"+f.Name.Lexeme+"' is reserved in enums."))
+			}
+		}
+	}
+
+	return r.VisitClassStmt(stmt.class())
+}
+
+// VisitInterfaceStmt checks the members of an interface are declared once.
+func (r *Resolver) VisitInterfaceStmt(stmt *Interface) interface{} {
+	r.Declare(stmt.Name)
+	r.Define(stmt.Name)
+	r.declare(stmt.Name, stmt)
+
+	members := map[string]bool{}
+	for _, member := range traitMembers(stmt.Methods, stmt.Getters, nil) {
+		if members[member] {
+			panic(NewLoxError(stmt.Name, "'"+member+"' is declared more than once in interface "+stmt.Name.Lexeme+"."))
+		}
+		members[member] = true
+	}
+	for _, functions := range [][]*Function{stmt.Methods, stmt.Getters} {
+		for _, f := range functions {
+			if isPrivate(f.Name.Lexeme) {
+				panic(NewLoxError(f.Name, "an interface cannot have private members."))
+			}
+		}
+	}
+	return nil
+}
+
+// VisitTraitStmt resolves the members of a trait like methods of a subclass,
+// because "super" refers to the superclass of the class composing the trait.
+func (r *Resolver) VisitTraitStmt(stmt *Trait) interface{} {
+	inClass := r.inClass
+	inSubClass := r.inSubClass
+	inStatic := r.inStatic
+
+	defer func() {
+		r.inClass = inClass
+		r.inSubClass = inSubClass
+		r.inStatic = inStatic
+	}()
+
+	r.Declare(stmt.Name)
+	r.Define(stmt.Name)
+	r.declare(stmt.Name, stmt)
+
+	for _, functions := range [][]*Function{stmt.Methods, stmt.Getters, stmt.Setters} {
+		for _, f := range functions {
+			if f.Name.Lexeme == "init" {
+				panic(NewLoxError(f.Name, "a trait cannot have an initializer."))
+			}
+			if isPrivate(f.Name.Lexeme) {
+				panic(NewLoxError(f.Name, "a trait cannot have private members."))
+			}
+		}
+	}
+
+	r.inClass = true
+	r.inSubClass = true
+	r.inStatic = false
+	r.privates = append(r.privates, nil)
+
+	r.BeginScope()
+	r.scopes.Peek()["super"] = varDefined
+	r.BeginScope()
+	r.scopes.Peek()["this"] = varDefined
+
+	for _, f := range stmt.Methods {
+		r.resolveFunction(f, FuncMeth)
+	}
+	for _, g := range stmt.Getters {
+		r.resolveFunction(g, FuncGetter)
+	}
+	for _, s := range stmt.Setters {
+		r.resolveFunction(s, FuncSetter)
+	}
+
+	r.EndScope()
+	r.EndScope()
+	r.privates = r.privates[:len(r.privates)-1]
 	return nil
 }
 
+// checkStaticNames reports private static members, static members are public, &
+// static members sharing a name, except a static getter & a static setter.
+func (r *Resolver) checkStaticNames(stmt *Class) {
+	names := []*Token{}
+	accessors := map[*Token]string{}
+	for _, f := range stmt.Statics {
+		names = append(names, f.Name)
+	}
+	for kind, functions := range [][]*Function{stmt.StaticGetters, stmt.StaticSetters} {
+		for _, f := range functions {
+			names = append(names, f.Name)
+			accessors[f.Name] = []string{"getter", "setter"}[kind]
+		}
+	}
+	for _, initializer := range stmt.Initializers {
+		switch field := initializer.(type) {
+		case *Var:
+			names = append(names, field.Name)
+		case *VarList:
+			for _, v := range field.stmts {
+				names = append(names, v.Name)
+			}
+		}
+	}
+
+	declared := map[string]map[string]bool{}
+	for _, name := range names {
+		if isPrivate(name.Lexeme) {
+			panic(NewLoxError(name, "static members can't be private."))
+		}
+
+		// a getter & a setter make a single property.
+		kind, kinds := accessors[name], declared[name.Lexeme]
+		if kind == "" {
+			kind = "member"
+		}
+		if len(kinds) > 0 && (kind == "member" || kinds["member"] || kinds[kind]) {
+			panic(NewLoxError(name, "static member '"+name.Lexeme+"' redeclared."))
+		}
+		if kinds == nil {
+			kinds = map[string]bool{}
+			declared[name.Lexeme] = kinds
+		}
+		kinds[kind] = true
+	}
+}
+
+// resolveStaticInitializers resolves static fields & static blocks.
+// Static fields are not variables, only their initializers are resolved.
+func (r *Resolver) resolveStaticInitializers(initializers []Stmt) {
+	enclosingFunc, inLoop := r.curFunc, r.inLoop
+	r.curFunc, r.inLoop = FuncNone, false
+	defer func() {
+		r.curFunc, r.inLoop = enclosingFunc, inLoop
+	}()
+
+	fields := map[string]bool{}
+	resolveField := func(field *Var) {
+		if fields[field.Name.Lexeme] {
+			panic(NewLoxError(field.Name, "static field redeclared."))
+		}
+		fields[field.Name.Lexeme] = true
+		if field.Initializer != nil {
+			r.resolve(field.Initializer)
+		}
+	}
+
+	for _, initializer := range initializers {
+		switch stmt := initializer.(type) {
+		case *Var:
+			resolveField(stmt)
+		case *VarList:
+			for _, field := range stmt.stmts {
+				resolveField(field)
+			}
+		default:
+			r.resolve(stmt)
+		}
+	}
+}
+
 // VisitControlStmt interpretes "break" & "return" statements.
 func (r *Resolver) VisitControlStmt(stmt *Control) interface{} {
 	if stmt.CtrlType == ControlReturn {
@@ -340,10 +922,18 @@ func (r *Resolver) resolveFunction(function *Function, fType FuncType) {
 	}()
 
 	r.BeginScope()
-	for _, param := range function.Params {
+	for index, param := range function.Params {
+		// defaults might refer to the previous parameters.
+		if index < len(function.Defaults) && function.Defaults[index] != nil {
+			r.resolve(function.Defaults[index])
+		}
 		r.Declare(param)
 		r.Define(param)
 	}
+	if function.Rest != nil {
+		r.Declare(function.Rest)
+		r.Define(function.Rest)
+	}
 	r.resolve(function.Body)
 	r.EndScope()
 
@@ -374,6 +964,17 @@ func (r *Resolver) VisitVarStmt(stmt *Var) interface{} {
 		r.resolve(stmt.Initializer)
 	}
 	r.Define(stmt.Name)
+	r.defineConst(stmt.Name, stmt.Constant)
+	return nil
+}
+
+// VisitVarDestructureStmt declares every name bound by the pattern.
+func (r *Resolver) VisitVarDestructureStmt(stmt *VarDestructure) interface{} {
+	r.resolve(stmt.Initializer)
+	r.resolvePattern(stmt.Pattern)
+	for _, name := range boundNames(stmt.Pattern) {
+		r.defineConst(name, stmt.Constant)
+	}
 	return nil
 }
 
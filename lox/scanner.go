@@ -21,26 +21,34 @@ type Scanner struct {
 }
 
 var keywords = map[string]TokenType{
-	"and":    TokenAnd,
-	"break":  TokenBreak,
-	"class":  TokenClass,
-	"else":   TokenElse,
-	"false":  TokenFalse,
-	"for":    TokenFor,
-	"fun":    TokenFun,
-	"get":    TokenGetter,
-	"if":     TokenIf,
-	"nil":    TokenNil,
-	"or":     TokenOr,
-	"print":  TokenPrint,
-	"return": TokenReturn,
-	"set":    TokenSetter,
-	"static": TokenStatic,
-	"super":  TokenSuper,
-	"this":   TokenThis,
-	"true":   TokenTrue,
-	"var":    TokenVar,
-	"while":  TokenWhile,
+	"and":       TokenAnd,
+	"break":     TokenBreak,
+	"case":      TokenCase,
+	"class":     TokenClass,
+	"const":     TokenConst,
+	"else":      TokenElse,
+	"enum":      TokenEnum,
+	"false":     TokenFalse,
+	"for":       TokenFor,
+	"fun":       TokenFun,
+	"get":       TokenGetter,
+	"if":        TokenIf,
+	"interface": TokenInterface,
+	"is":        TokenIs,
+	"match":     TokenMatch,
+	"nil":       TokenNil,
+	"or":        TokenOr,
+	"print":     TokenPrint,
+	"return":    TokenReturn,
+	"set":       TokenSetter,
+	"static":    TokenStatic,
+	"super":     TokenSuper,
+	"this":      TokenThis,
+	"trait":     TokenTrait,
+	"true":      TokenTrue,
+	"var":       TokenVar,
+	"while":     TokenWhile,
+	"with":      TokenWith,
 }
 
 // NewScanner returns a new s.
@@ -92,9 +100,21 @@ func (s *Scanner) scanToken() {
 	case ']':
 		s.addToken(TokenRightBracket, nil)
 	case '.':
-		s.addToken(TokenDot, nil)
+		// ".." separates the bounds of ranges, "..." marks rest patterns.
+		switch {
+		case s.peek() == '.' && s.peekNext() == '.':
+			s.advance()
+			s.advance()
+			s.addToken(TokenEllipsis, nil)
+		case s.match('.'):
+			s.addToken(TokenDotDot, nil)
+		default:
+			s.addToken(TokenDot, nil)
+		}
 	case ',':
 		s.addToken(TokenComma, nil)
+	case ':':
+		s.addToken(TokenColon, nil)
 	case ';':
 		s.addToken(TokenSemi, nil)
 
@@ -119,6 +139,18 @@ func (s *Scanner) scanToken() {
 		s.addIfMatch('=', TokenLessEqual, TokenLess)
 	case '>':
 		s.addIfMatch('=', TokenGreaterEqual, TokenGreater)
+	case '?':
+		// "?[" must be written without spaces, "c ? [a] : b" is a conditional.
+		switch {
+		case s.match('?'):
+			s.addToken(TokenQuestionQuestion, nil)
+		case s.match('.'):
+			s.addToken(TokenQuestionDot, nil)
+		case s.match('['):
+			s.addToken(TokenQuestionBracket, nil)
+		default:
+			s.addToken(TokenQuestion, nil)
+		}
 
 	case '/':
 		if s.match('/') {
@@ -141,6 +173,13 @@ func (s *Scanner) scanToken() {
 	case '"':
 		s.string()
 
+	// private names, e.g. #name.
+	case '#':
+		if !alpha(s.peek()) {
+			panic(NewLexingError(s.line, "expect a name after '#'."))
+		}
+		s.identifier()
+
 	default:
 		if alpha(c) {
 			// s.identifier covers keywords.
@@ -9,6 +9,7 @@ const (
 	varUndeclared varStatus = iota // variable is not declared.
 	varDeclared                    // variable is declared but not available for reference.
 	varDefined                     // variable is available for reference.
+	varConst                       // constant is available for reference, but can't be assigned.
 )
 
 // Scope is a map with key = name of a variable, indicates the status of the variable.
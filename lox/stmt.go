@@ -4,11 +4,15 @@ type StmtVisitor interface {
 	VisitBlockStmt(stmt *Block) interface{}
 	VisitClassStmt(stmt *Class) interface{}
 	VisitControlStmt(stmt *Control) interface{}
+	VisitEnumStmt(stmt *Enum) interface{}
 	VisitFunctionStmt(stmt *Function) interface{}
 	VisitExpressionStmt(stmt *Expression) interface{}
 	VisitIfStmt(stmt *If) interface{}
+	VisitInterfaceStmt(stmt *Interface) interface{}
 	VisitPrintStmt(stmt *Print) interface{}
+	VisitTraitStmt(stmt *Trait) interface{}
 	VisitVarStmt(stmt *Var) interface{}
+	VisitVarDestructureStmt(stmt *VarDestructure) interface{}
 	VisitVarListStmt(stmt *VarList) interface{}
 	VisitWhileStmt(stmt *While) interface{}
 }
@@ -29,16 +33,21 @@ func (expr *Block) Accept(v StmtVisitor) interface{} {
 }
 
 type Class struct {
-	Name    *Token
-	Super   *Variable
-	Statics []*Function
-	Methods []*Function
-	Getters []*Function
-	Setters []*Function
-}
-
-func NewClass(name *Token, super *Variable, statics []*Function, methods []*Function, getters []*Function, setters []*Function) Stmt {
-	return &Class{Name: name, Super: super, Statics: statics, Methods: methods, Getters: getters, Setters: setters}
+	Name          *Token
+	Super         *Variable
+	Traits        []*Variable
+	Interfaces    []*Variable
+	Statics       []*Function
+	Methods       []*Function
+	Getters       []*Function
+	Setters       []*Function
+	StaticGetters []*Function
+	StaticSetters []*Function
+	Initializers  []Stmt
+}
+
+func NewClass(name *Token, super *Variable, traits []*Variable, interfaces []*Variable, statics []*Function, methods []*Function, getters []*Function, setters []*Function, staticgetters []*Function, staticsetters []*Function, initializers []Stmt) Stmt {
+	return &Class{Name: name, Super: super, Traits: traits, Interfaces: interfaces, Statics: statics, Methods: methods, Getters: getters, Setters: setters, StaticGetters: staticgetters, StaticSetters: staticsetters, Initializers: initializers}
 }
 func (expr *Class) Accept(v StmtVisitor) interface{} {
 	return v.VisitClassStmt(expr)
@@ -57,14 +66,31 @@ func (expr *Control) Accept(v StmtVisitor) interface{} {
 	return v.VisitControlStmt(expr)
 }
 
+type Enum struct {
+	Name    *Token
+	Members []*Token
+	Methods []*Function
+	Getters []*Function
+	Setters []*Function
+}
+
+func NewEnum(name *Token, members []*Token, methods []*Function, getters []*Function, setters []*Function) Stmt {
+	return &Enum{Name: name, Members: members, Methods: methods, Getters: getters, Setters: setters}
+}
+func (expr *Enum) Accept(v StmtVisitor) interface{} {
+	return v.VisitEnumStmt(expr)
+}
+
 type Function struct {
-	Name   *Token
-	Params []*Token
-	Body   []Stmt
+	Name     *Token
+	Params   []*Token
+	Defaults []Expr
+	Rest     *Token
+	Body     []Stmt
 }
 
-func NewFunction(name *Token, params []*Token, body []Stmt) Stmt {
-	return &Function{Name: name, Params: params, Body: body}
+func NewFunction(name *Token, params []*Token, defaults []Expr, rest *Token, body []Stmt) Stmt {
+	return &Function{Name: name, Params: params, Defaults: defaults, Rest: rest, Body: body}
 }
 func (expr *Function) Accept(v StmtVisitor) interface{} {
 	return v.VisitFunctionStmt(expr)
@@ -94,6 +120,19 @@ func (expr *If) Accept(v StmtVisitor) interface{} {
 	return v.VisitIfStmt(expr)
 }
 
+type Interface struct {
+	Name    *Token
+	Methods []*Function
+	Getters []*Function
+}
+
+func NewInterface(name *Token, methods []*Function, getters []*Function) Stmt {
+	return &Interface{Name: name, Methods: methods, Getters: getters}
+}
+func (expr *Interface) Accept(v StmtVisitor) interface{} {
+	return v.VisitInterfaceStmt(expr)
+}
+
 type Print struct {
 	Expression Expr
 }
@@ -105,18 +144,47 @@ func (expr *Print) Accept(v StmtVisitor) interface{} {
 	return v.VisitPrintStmt(expr)
 }
 
+type Trait struct {
+	Name    *Token
+	Methods []*Function
+	Getters []*Function
+	Setters []*Function
+}
+
+func NewTrait(name *Token, methods []*Function, getters []*Function, setters []*Function) Stmt {
+	return &Trait{Name: name, Methods: methods, Getters: getters, Setters: setters}
+}
+func (expr *Trait) Accept(v StmtVisitor) interface{} {
+	return v.VisitTraitStmt(expr)
+}
+
 type Var struct {
 	Name        *Token
 	Initializer Expr
+	Constant    bool
 }
 
-func NewVar(name *Token, initializer Expr) Stmt {
-	return &Var{Name: name, Initializer: initializer}
+func NewVar(name *Token, initializer Expr, constant bool) Stmt {
+	return &Var{Name: name, Initializer: initializer, Constant: constant}
 }
 func (expr *Var) Accept(v StmtVisitor) interface{} {
 	return v.VisitVarStmt(expr)
 }
 
+type VarDestructure struct {
+	Keyword     *Token
+	Pattern     Pattern
+	Initializer Expr
+	Constant    bool
+}
+
+func NewVarDestructure(keyword *Token, pattern Pattern, initializer Expr, constant bool) Stmt {
+	return &VarDestructure{Keyword: keyword, Pattern: pattern, Initializer: initializer, Constant: constant}
+}
+func (expr *VarDestructure) Accept(v StmtVisitor) interface{} {
+	return v.VisitVarDestructureStmt(expr)
+}
+
 type VarList struct {
 	stmts []*Var
 }
@@ -0,0 +1,200 @@
+package lox
+
+import (
+	"math"
+	"strings"
+	"unicode/utf8"
+)
+
+// LoxString is the runtime object for the builtin String class.
+// Strings are plain go strings at runtime. Accessing a property of a string
+// wraps it in a String instance, with the string stored out of reach of scripts.
+// Lengths & indices are counted in code points.
+var LoxString *LoxClass
+
+// newStringInstance wraps `s` to access the methods of String.
+func newStringInstance(s string) *LoxInstance {
+	instance := NewLoxInstance(LoxString)
+	instance.native = s
+	return instance
+}
+
+func stringValue(i *LoxInstance) string {
+	s, _ := i.native.(string)
+	return s
+}
+
+// newString charges the memory for a string about to be created.
+func newString(interp *Interpreter, length int) {
+	interp.alloc(sizeString + length)
+}
+
+// clampIndex converts a possibly negative index into [0, length].
+func clampIndex(index, length int) int {
+	if index < 0 {
+		index += length
+	}
+	if index < 0 {
+		return 0
+	}
+	if index > length {
+		return length
+	}
+	return index
+}
+
+// stringMethod wraps a method that takes string args only.
+func stringMethod(name string, arity int, fn func(s string, args ...string) interface{}) *BuiltInFunc {
+	return NewBuiltinFunc(arity, func(interp *Interpreter, i *LoxInstance, args ...interface{}) interface{} {
+		strArgs := make([]string, len(args))
+		for index := range args {
+			strArgs[index] = stringArg(name, args, index)
+		}
+		return fn(stringValue(i), strArgs...)
+	})
+}
+
+// pad returns the padding needed to extend `s` to `length` code points.
+func pad(interp *Interpreter, name string, i *LoxInstance, args []interface{}) (string, string) {
+	checkArgs(name, args, 1, 2)
+	s := stringValue(i)
+	length := intArg(name, args, 0)
+	fill := " "
+	if len(args) == 2 {
+		fill = stringArg(name, args, 1)
+	}
+
+	missing := length - utf8.RuneCountInString(s)
+	if missing <= 0 || fill == "" {
+		return s, ""
+	}
+
+	fillRunes := []rune(fill)
+	newString(interp, len(s)+missing*utf8.UTFMax)
+	padding := strings.Repeat(fill, missing/len(fillRunes)) + string(fillRunes[:missing%len(fillRunes)])
+	return s, padding
+}
+
+// init String class. This function will be called when an Interpreter is instantiated.
+func initString() {
+	var methods = map[string]Callable{
+		// String(value) converts a value to string.
+		"init": NewBuiltinFunc(1, func(interp *Interpreter, i *LoxInstance, args ...interface{}) interface{} {
+			if s, ok := args[0].(string); ok {
+				return s
+			}
+			s := interp.stringify(args[0])
+			newString(interp, len(s))
+			return s
+		}),
+		"charAt": NewBuiltinFunc(1, func(interp *Interpreter, i *LoxInstance, args ...interface{}) interface{} {
+			runes := []rune(stringValue(i))
+			index := intArg("charAt", args, 0)
+			if index < 0 || index >= len(runes) {
+				return nil
+			}
+			return string(runes[index])
+		}),
+		"indexOf": stringMethod("indexOf", 1, func(s string, args ...string) interface{} {
+			index := strings.Index(s, args[0])
+			if index < 0 {
+				return -1
+			}
+			return utf8.RuneCountInString(s[:index])
+		}),
+		"contains": stringMethod("contains", 1, func(s string, args ...string) interface{} {
+			return strings.Contains(s, args[0])
+		}),
+		"startsWith": stringMethod("startsWith", 1, func(s string, args ...string) interface{} {
+			return strings.HasPrefix(s, args[0])
+		}),
+		"endsWith": stringMethod("endsWith", 1, func(s string, args ...string) interface{} {
+			return strings.HasSuffix(s, args[0])
+		}),
+		// slice(start, end?) supports negative indices counting from the end.
+		"slice": NewBuiltinFunc(-1, func(interp *Interpreter, i *LoxInstance, args ...interface{}) interface{} {
+			checkArgs("slice", args, 1, 2)
+			runes := []rune(stringValue(i))
+			start := clampIndex(intArg("slice", args, 0), len(runes))
+			end := len(runes)
+			if len(args) == 2 {
+				end = clampIndex(intArg("slice", args, 1), len(runes))
+			}
+			if start >= end {
+				return ""
+			}
+			sliced := string(runes[start:end])
+			newString(interp, len(sliced))
+			return sliced
+		}),
+		// split("") splits a string into code points.
+		"split": NewBuiltinFunc(1, func(interp *Interpreter, i *LoxInstance, args ...interface{}) interface{} {
+			parts := strings.Split(stringValue(i), stringArg("split", args, 0))
+			list := make([]interface{}, len(parts))
+			for index, part := range parts {
+				newString(interp, len(part))
+				list[index] = part
+			}
+			return newArray(interp, list)
+		}),
+		"trim": NewBuiltinFunc(0, func(interp *Interpreter, i *LoxInstance, args ...interface{}) interface{} {
+			return strings.TrimSpace(stringValue(i))
+		}),
+		"upper": NewBuiltinFunc(0, func(interp *Interpreter, i *LoxInstance, args ...interface{}) interface{} {
+			s := strings.ToUpper(stringValue(i))
+			newString(interp, len(s))
+			return s
+		}),
+		"lower": NewBuiltinFunc(0, func(interp *Interpreter, i *LoxInstance, args ...interface{}) interface{} {
+			s := strings.ToLower(stringValue(i))
+			newString(interp, len(s))
+			return s
+		}),
+		// replace(old, new) replaces all occurrences of `old`.
+		"replace": NewBuiltinFunc(2, func(interp *Interpreter, i *LoxInstance, args ...interface{}) interface{} {
+			s := stringValue(i)
+			old, new := stringArg("replace", args, 0), stringArg("replace", args, 1)
+			if count := strings.Count(s, old); count > 0 {
+				newString(interp, len(s)+count*(len(new)-len(old)))
+			}
+			return strings.ReplaceAll(s, old, new)
+		}),
+		"repeat": NewBuiltinFunc(1, func(interp *Interpreter, i *LoxInstance, args ...interface{}) interface{} {
+			s := stringValue(i)
+			count := intArg("repeat", args, 0)
+			if count < 0 {
+				panic(NewRuntimeError(nil, "repeat() expects a non-negative count."))
+			}
+			if len(s) > 0 && count > (math.MaxInt-sizeString)/len(s) {
+				panic(NewRuntimeError(nil, "repeat() result is too long."))
+			}
+			// charge before building, the result might be huge.
+			newString(interp, len(s)*count)
+			return strings.Repeat(s, count)
+		}),
+		"padStart": NewBuiltinFunc(-1, func(interp *Interpreter, i *LoxInstance, args ...interface{}) interface{} {
+			s, padding := pad(interp, "padStart", i, args)
+			return padding + s
+		}),
+		"padEnd": NewBuiltinFunc(-1, func(interp *Interpreter, i *LoxInstance, args ...interface{}) interface{} {
+			s, padding := pad(interp, "padEnd", i, args)
+			return s + padding
+		}),
+		"codePoints": NewBuiltinFunc(0, func(interp *Interpreter, i *LoxInstance, args ...interface{}) interface{} {
+			runes := []rune(stringValue(i))
+			list := make([]interface{}, len(runes))
+			for index, r := range runes {
+				list[index] = int(r)
+			}
+			return newArray(interp, list)
+		}),
+	}
+
+	var getters = map[string]Callable{
+		"length": NewBuiltinFunc(0, func(interp *Interpreter, i *LoxInstance, args ...interface{}) interface{} {
+			return utf8.RuneCountInString(stringValue(i))
+		}),
+	}
+
+	LoxString = NewLoxClass("String", nil, nil, methods, getters, nil)
+}
@@ -0,0 +1,197 @@
+package lox
+
+import (
+	"fmt"
+	"time"
+)
+
+// LoxTime is the runtime object for the builtin Time module.
+// Layouts for formatting & parsing are go layouts, e.g. "2006-01-02 15:04:05",
+// the common ones are available as Time.RFC3339, Time.DateTime, Time.Date & Time.Clock.
+var LoxTime *LoxClass
+
+// LoxDateTime is the class of points in time, created by Time.now, Time.parse or DateTime(...).
+// The time is stored out of reach of scripts.
+var LoxDateTime *LoxClass
+
+// Clock is where an interpreter gets the current time from, see Interpreter.SetClock.
+type Clock interface {
+	Now() time.Time
+	Sleep(d time.Duration)
+}
+
+// systemClock is the default Clock.
+type systemClock struct{}
+
+func (systemClock) Now() time.Time { return time.Now() }
+
+func (systemClock) Sleep(d time.Duration) { time.Sleep(d) }
+
+// SetClock makes the interpreter read the time from `clock`, e.g. a fake one in tests.
+func (i *Interpreter) SetClock(clock Clock) {
+	i.clock = clock
+	i.started = clock.Now()
+}
+
+func newDateTime(interp *Interpreter, t time.Time) *LoxInstance {
+	interp.alloc(sizeInstance + sizeSlot)
+	instance := NewLoxInstance(LoxDateTime)
+	instance.native = t
+	return instance
+}
+
+func dateTimeValue(i *LoxInstance) time.Time {
+	t, _ := i.native.(time.Time)
+	return t
+}
+
+// dateTimeArg returns args[index] as a time for native function `name`.
+func dateTimeArg(name string, args []interface{}, index int) time.Time {
+	if instance, ok := args[index].(*LoxInstance); ok && instance.class == LoxDateTime {
+		return dateTimeValue(instance)
+	}
+	panic(NewRuntimeError(nil, fmt.Sprintf("%v() expects argument %v to be a DateTime.", name, index+1)))
+}
+
+// millis converts a number of milliseconds into a duration.
+func millis(name string, args []interface{}, index int) time.Duration {
+	return time.Duration(numberArg(name, args, index) * float64(time.Millisecond))
+}
+
+// dateTimeGetter wraps a getter reading a component of the time.
+func dateTimeGetter(get func(t time.Time) int) *BuiltInFunc {
+	return NewBuiltinFunc(0, func(interp *Interpreter, i *LoxInstance, args ...interface{}) interface{} {
+		return get(dateTimeValue(i))
+	})
+}
+
+// dateTimeMethod wraps a method returning a new DateTime.
+func dateTimeMethod(arity int, fn func(t time.Time, args []interface{}) time.Time) *BuiltInFunc {
+	return NewBuiltinFunc(arity, func(interp *Interpreter, i *LoxInstance, args ...interface{}) interface{} {
+		return newDateTime(interp, fn(dateTimeValue(i), args))
+	})
+}
+
+func formatTime(interp *Interpreter, t time.Time, layout string) string {
+	formatted := t.Format(layout)
+	interp.alloc(sizeString + len(formatted))
+	return formatted
+}
+
+// defineClock defines the canonical clock() native, returning the seconds since the epoch.
+func (i *Interpreter) defineClock() {
+	i.global.Define("clock", NewBuiltinFunc(0, func(interp *Interpreter, _ *LoxInstance, args ...interface{}) interface{} {
+		return float64(interp.clock.Now().UnixNano()) / float64(time.Second)
+	}))
+}
+
+// init Time module & DateTime class. This function will be called when an Interpreter is instantiated.
+func initTime() {
+	var statics = map[string]Callable{
+		"now": NewBuiltinFunc(0, func(interp *Interpreter, i *LoxInstance, args ...interface{}) interface{} {
+			return newDateTime(interp, interp.clock.Now())
+		}),
+		// elapsed() returns the milliseconds since the interpreter started, it is monotonic.
+		"elapsed": NewBuiltinFunc(0, func(interp *Interpreter, i *LoxInstance, args ...interface{}) interface{} {
+			return float64(interp.clock.Now().Sub(interp.started)) / float64(time.Millisecond)
+		}),
+		"sleep": NewBuiltinFunc(1, func(interp *Interpreter, i *LoxInstance, args ...interface{}) interface{} {
+			if d := millis("sleep", args, 0); d > 0 {
+				interp.clock.Sleep(d)
+			}
+			return nil
+		}),
+		"format": NewBuiltinFunc(2, func(interp *Interpreter, i *LoxInstance, args ...interface{}) interface{} {
+			return formatTime(interp, dateTimeArg("format", args, 0), stringArg("format", args, 1))
+		}),
+		// parse(s, layout) parses a time in UTC, unless the layout has a time zone.
+		"parse": NewBuiltinFunc(2, func(interp *Interpreter, i *LoxInstance, args ...interface{}) interface{} {
+			t, err := time.Parse(stringArg("parse", args, 1), stringArg("parse", args, 0))
+			if err != nil {
+				panic(NewRuntimeError(nil, fmt.Sprintf("parse(): %v.", err)))
+			}
+			return newDateTime(interp, t)
+		}),
+		// fromMillis(ms) returns the time `ms` milliseconds after the epoch, in UTC.
+		"fromMillis": NewBuiltinFunc(1, func(interp *Interpreter, i *LoxInstance, args ...interface{}) interface{} {
+			return newDateTime(interp, time.Unix(0, 0).UTC().Add(millis("fromMillis", args, 0)))
+		}),
+	}
+
+	var methods = map[string]Callable{
+		// DateTime(year, month, day, hour?, minute?, second?, millisecond?) creates a time in UTC.
+		"init": NewBuiltinFunc(-1, func(interp *Interpreter, i *LoxInstance, args ...interface{}) interface{} {
+			checkArgs("DateTime", args, 3, 7)
+			parts := [7]int{}
+			for index := range args {
+				parts[index] = intArg("DateTime", args, index)
+			}
+			i.native = time.Date(parts[0], time.Month(parts[1]), parts[2],
+				parts[3], parts[4], parts[5], parts[6]*int(time.Millisecond), time.UTC)
+			return nil
+		}),
+		"format": NewBuiltinFunc(1, func(interp *Interpreter, i *LoxInstance, args ...interface{}) interface{} {
+			return formatTime(interp, dateTimeValue(i), stringArg("format", args, 0))
+		}),
+		"toString": NewBuiltinFunc(0, func(interp *Interpreter, i *LoxInstance, args ...interface{}) interface{} {
+			return formatTime(interp, dateTimeValue(i), time.RFC3339Nano)
+		}),
+		// add(ms) returns the time `ms` milliseconds later.
+		"add": dateTimeMethod(1, func(t time.Time, args []interface{}) time.Time {
+			return t.Add(millis("add", args, 0))
+		}),
+		// addDays, addMonths & addYears normalize overflowing dates, e.g. Jan 31 + 1 month is Mar 3 (or 2).
+		"addDays": dateTimeMethod(1, func(t time.Time, args []interface{}) time.Time {
+			return t.AddDate(0, 0, intArg("addDays", args, 0))
+		}),
+		"addMonths": dateTimeMethod(1, func(t time.Time, args []interface{}) time.Time {
+			return t.AddDate(0, intArg("addMonths", args, 0), 0)
+		}),
+		"addYears": dateTimeMethod(1, func(t time.Time, args []interface{}) time.Time {
+			return t.AddDate(intArg("addYears", args, 0), 0, 0)
+		}),
+		"utc": dateTimeMethod(0, func(t time.Time, args []interface{}) time.Time {
+			return t.UTC()
+		}),
+		"local": dateTimeMethod(0, func(t time.Time, args []interface{}) time.Time {
+			return t.Local()
+		}),
+		// diff(other) returns the milliseconds from `other` to this time.
+		"diff": NewBuiltinFunc(1, func(interp *Interpreter, i *LoxInstance, args ...interface{}) interface{} {
+			return float64(dateTimeValue(i).Sub(dateTimeArg("diff", args, 0))) / float64(time.Millisecond)
+		}),
+		"before": NewBuiltinFunc(1, func(interp *Interpreter, i *LoxInstance, args ...interface{}) interface{} {
+			return dateTimeValue(i).Before(dateTimeArg("before", args, 0))
+		}),
+		"after": NewBuiltinFunc(1, func(interp *Interpreter, i *LoxInstance, args ...interface{}) interface{} {
+			return dateTimeValue(i).After(dateTimeArg("after", args, 0))
+		}),
+		"equals": NewBuiltinFunc(1, func(interp *Interpreter, i *LoxInstance, args ...interface{}) interface{} {
+			return dateTimeValue(i).Equal(dateTimeArg("equals", args, 0))
+		}),
+	}
+
+	var getters = map[string]Callable{
+		"year":   dateTimeGetter(func(t time.Time) int { return t.Year() }),
+		"month":  dateTimeGetter(func(t time.Time) int { return int(t.Month()) }),
+		"day":    dateTimeGetter(func(t time.Time) int { return t.Day() }),
+		"hour":   dateTimeGetter(func(t time.Time) int { return t.Hour() }),
+		"minute": dateTimeGetter(func(t time.Time) int { return t.Minute() }),
+		"second": dateTimeGetter(func(t time.Time) int { return t.Second() }),
+		"millisecond": dateTimeGetter(func(t time.Time) int {
+			return t.Nanosecond() / int(time.Millisecond)
+		}),
+		// weekday counts from Sunday, which is 0.
+		"weekday": dateTimeGetter(func(t time.Time) int { return int(t.Weekday()) }),
+		"yearDay": dateTimeGetter(func(t time.Time) int { return t.YearDay() }),
+		// millis is the number of milliseconds since the epoch.
+		"millis": dateTimeGetter(func(t time.Time) int { return int(t.UnixMilli()) }),
+	}
+
+	LoxTime = NewLoxClass("Time", nil, statics, nil, nil, nil)
+	LoxTime.Fields["RFC3339"] = time.RFC3339
+	LoxTime.Fields["DateTime"] = time.DateTime
+	LoxTime.Fields["Date"] = time.DateOnly
+	LoxTime.Fields["Clock"] = time.TimeOnly
+	LoxDateTime = NewLoxClass("DateTime", nil, nil, methods, getters, nil)
+}
@@ -0,0 +1,92 @@
+package lox
+
+import (
+	"testing"
+	"time"
+)
+
+// fakeClock is a Clock whose time only moves when the script sleeps.
+type fakeClock struct {
+	now time.Time
+}
+
+func (c *fakeClock) Now() time.Time { return c.now }
+
+func (c *fakeClock) Sleep(d time.Duration) { c.now = c.now.Add(d) }
+
+func newTimeInterpreter() (*Interpreter, *fakeClock) {
+	clock := &fakeClock{time.Date(2024, time.February, 29, 13, 45, 30, 0, time.UTC)}
+	interpreter := NewInterpreter(false)
+	interpreter.SetClock(clock)
+	return interpreter, clock
+}
+
+func TestTimeClock(t *testing.T) {
+	interpreter, clock := newTimeInterpreter()
+	src := `
+	var start = clock()
+	var now = Time.now()
+	Time.sleep(1500)
+	var elapsed = Time.elapsed()
+	var slept = clock() - start
+	`
+	if runWith(t, interpreter, src) {
+		t.Fatalf("unexpected runtime error: %v", interpreter.LastError())
+	}
+
+	global := interpreter.global.values
+	if !dateTimeValue(global["now"].(*LoxInstance)).Equal(clock.now.Add(-1500 * time.Millisecond)) {
+		t.Errorf("unexpected now %v", global["now"])
+	}
+	if global["elapsed"] != 1500.0 || global["slept"] != 1.5 {
+		t.Errorf("unexpected elapsed %v, %v", global["elapsed"], global["slept"])
+	}
+}
+
+func TestDateTime(t *testing.T) {
+	interpreter, _ := newTimeInterpreter()
+	src := `
+	var now = Time.now()
+	var date = DateTime(2024, 1, 31)
+	// the time is out of reach of scripts.
+	date.time = 1
+	var parsed = Time.parse("2024-03-01 08:00:00", Time.DateTime)
+	`
+	if runWith(t, interpreter, src) {
+		t.Fatalf("unexpected runtime error: %v", interpreter.LastError())
+	}
+
+	expectations := map[string]interface{}{
+		"now.year":                                2024,
+		"now.month":                               2,
+		"now.day":                                 29,
+		"now.hour":                                13,
+		"now.weekday":                             4,
+		"now.yearDay":                             60,
+		"now.format(Time.Date)":                   "2024-02-29",
+		"Time.format(now, \"15:04\")":             "13:45",
+		"now.addYears(1).format(Time.Date)":       "2025-03-01",
+		"now.addDays(1).day":                      1,
+		"date.addMonths(1).format(Time.Date)":     "2024-03-02",
+		"date.year":                               2024,
+		"parsed.diff(now) / 1000":                 65670.0,
+		"parsed.after(now)":                       true,
+		"now.add(1000).second":                    31,
+		"Time.fromMillis(0).toString()":           "1970-01-01T00:00:00Z",
+		"Time.fromMillis(now.millis).equals(now)": true,
+	}
+	for expr, expected := range expectations {
+		if runWith(t, interpreter, "var result = "+expr+";") {
+			t.Errorf("unexpected runtime error evaluating %v.", expr)
+		} else if value := interpreter.global.values["result"]; value != expected {
+			t.Errorf("expect %v to be %v, but got %v", expr, expected, value)
+		}
+	}
+}
+
+func TestTimeParseError(t *testing.T) {
+	interpreter, _ := newTimeInterpreter()
+	if !runWith(t, interpreter, "Time.parse(\"yesterday\", Time.Date);") {
+		t.Error("expect a runtime error for an invalid time.")
+	}
+}
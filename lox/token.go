@@ -18,6 +18,7 @@ const (
 	TokenLeftBracket
 	TokenRightBracket
 	TokenComma
+	TokenColon
 	TokenDot
 	TokenMinus
 	TokenPlus
@@ -46,6 +47,12 @@ const (
 	TokenGreaterEqual
 	TokenLess
 	TokenLessEqual
+	TokenQuestion
+	TokenQuestionQuestion
+	TokenQuestionDot
+	TokenQuestionBracket
+	TokenDotDot
+	TokenEllipsis
 
 	// literal
 	TokenString
@@ -55,13 +62,19 @@ const (
 	// keywords
 	TokenAnd
 	TokenBreak
+	TokenCase
 	TokenClass
+	TokenConst
 	TokenFalse
 	TokenElse
+	TokenEnum
 	TokenFor
 	TokenFun
 	TokenGetter
 	TokenIf
+	TokenInterface
+	TokenIs
+	TokenMatch
 	TokenNil
 	TokenOr
 	TokenReturn
@@ -69,9 +82,11 @@ const (
 	TokenStatic
 	TokenSuper
 	TokenThis
+	TokenTrait
 	TokenTrue
 	TokenVar
 	TokenWhile
+	TokenWith
 
 	TokenEOF
 )
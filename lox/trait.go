@@ -0,0 +1,109 @@
+package lox
+
+// Traits hold methods, getters & setters that classes compose with `with`, e.g.
+// `class Foo < Bar with A, B`. The members of the traits are looked up after the
+// ones of the class & before the ones of the superclass, so that a class overrides
+// the members of its traits, which override the members of the superclass.
+// Inside a trait, `super` refers to the superclass of the class composing it.
+// Inside a class, `super` refers to the members of its traits first.
+//
+// A member provided by more than one trait is a conflict, unless the class
+// overrides it. The resolver reports conflicts of the traits it knows statically,
+// the interpreter reports all of them when the class is declared.
+
+// LoxTrait is the runtime object for a trait.
+type LoxTrait struct {
+	Name        string
+	Declaration *Trait
+	Enclosing   *Environment
+}
+
+// NewLoxTrait returns a runtime object for a trait.
+func NewLoxTrait(declaration *Trait, enclosing *Environment) *LoxTrait {
+	return &LoxTrait{declaration.Name.Lexeme, declaration, enclosing}
+}
+
+func (t *LoxTrait) String() string {
+	return "<trait " + t.Name + ">"
+}
+
+// traitMembers returns the keys of the members of a class or a trait, getters &
+// setters are distinguished from methods by a prefix.
+func traitMembers(methods, getters, setters []*Function) []string {
+	members := make([]string, 0, len(methods)+len(getters)+len(setters))
+	for _, method := range methods {
+		members = append(members, method.Name.Lexeme)
+	}
+	for _, getter := range getters {
+		members = append(members, "get "+getter.Name.Lexeme)
+	}
+	for _, setter := range setters {
+		members = append(members, "set "+setter.Name.Lexeme)
+	}
+	return members
+}
+
+// traitConflict returns an error message for the first trait composed twice, or
+// the first member provided by two of `traits` & not overridden by `class`.
+// Traits are checked in the order they are listed, so the result is deterministic.
+func traitConflict(class *Class, traits []*Trait) string {
+	overridden := map[string]bool{}
+	for _, member := range traitMembers(class.Methods, class.Getters, class.Setters) {
+		overridden[member] = true
+	}
+
+	providers := map[string]*Trait{}
+	for index, trait := range traits {
+		for _, other := range traits[:index] {
+			if other == trait {
+				return "trait '" + trait.Name.Lexeme + "' is composed more than once."
+			}
+		}
+
+		for _, member := range traitMembers(trait.Methods, trait.Getters, trait.Setters) {
+			if overridden[member] {
+				continue
+			}
+			if provider, ok := providers[member]; ok {
+				return "'" + member + "' is provided by both " + provider.Name.Lexeme + " and " +
+					trait.Name.Lexeme + ", class " + class.Name.Lexeme + " must override it."
+			}
+			providers[member] = trait
+		}
+	}
+	return ""
+}
+
+// composeTraits returns a class holding the members of `traits`, which is put
+// between the class composing them & its superclass `super`.
+func (i *Interpreter) composeTraits(class *Class, super *LoxClass, traits []*LoxTrait) *LoxClass {
+	decls := make([]*Trait, len(traits))
+	for index, trait := range traits {
+		decls[index] = trait.Declaration
+	}
+	if message := traitConflict(class, decls); message != "" {
+		panic(NewRuntimeError(class.Name, message))
+	}
+
+	i.alloc(sizeClass)
+	mixin := NewLoxClass(class.Name.Lexeme, super, nil, map[string]Callable{}, map[string]Callable{}, map[string]Callable{})
+	mixin.mixin = true
+	for _, trait := range traits {
+		// "super" in the members of a trait is the superclass of the class composing it.
+		env := NewEnvironment(trait.Enclosing)
+		env.Define("super", super)
+
+		decl := trait.Declaration
+		i.alloc(sizeEnv + sizeFunction*(len(decl.Methods)+len(decl.Getters)+len(decl.Setters)))
+		for _, method := range decl.Methods {
+			mixin.Methods[method.Name.Lexeme] = NewLoxFunction(method, env)
+		}
+		for _, getter := range decl.Getters {
+			mixin.Getters[getter.Name.Lexeme] = NewLoxFunction(getter, env)
+		}
+		for _, setter := range decl.Setters {
+			mixin.Setters[setter.Name.Lexeme] = NewLoxFunction(setter, env)
+		}
+	}
+	return mixin
+}
@@ -0,0 +1,89 @@
+package lox
+
+// `value is Type` reports whether `value` is an instance of the class `Type` or
+// of one of its subclasses. `Type` might also be a trait composed by the class of
+// `value`, or an interface it declares to implement. `type(value)` returns the
+// name of the type of `value` & `classOf(value)` returns the class of an instance
+// or of a string, which is String.
+
+// classOf returns the class of `value`, or nil if it isn't an instance or a string.
+func classOf(value interface{}) *LoxClass {
+	switch val := value.(type) {
+	case *LoxInstance:
+		return val.class
+	case *_arrayInsType:
+		return val.class
+	case string:
+		return LoxString
+	}
+	return nil
+}
+
+// isInstance evaluates `value is kind`.
+func isInstance(operator *Token, value, kind interface{}) bool {
+	class := classOf(value)
+
+	switch kind := kind.(type) {
+	case *LoxClass:
+		return class != nil && class.isSubclassOf(kind)
+	case *LoxTrait:
+		for ; class != nil; class = class.Super {
+			for _, trait := range class.Traits {
+				if trait == kind {
+					return true
+				}
+			}
+		}
+		return false
+	case *LoxInterface:
+		for ; class != nil; class = class.Super {
+			for _, iface := range class.Interfaces {
+				if iface == kind {
+					return true
+				}
+			}
+		}
+		return false
+	}
+	panic(NewRuntimeError(operator, "right operand of 'is' must be a class, a trait or an interface."))
+}
+
+// typeName returns the name of the type of `value`.
+func typeName(value interface{}) string {
+	switch value.(type) {
+	case nil:
+		return "nil"
+	case bool:
+		return "bool"
+	case int, float64:
+		return "number"
+	case string:
+		return "string"
+	case *LoxClass:
+		return "class"
+	case *LoxTrait:
+		return "trait"
+	case *LoxInterface:
+		return "interface"
+	case Callable:
+		return "function"
+	case *LoxInstance, *_arrayInsType:
+		return "instance"
+	}
+	return "native"
+}
+
+// defineTypes defines the builtin functions introspecting types.
+func (i *Interpreter) defineTypes() {
+	i.global.Define("type", NewBuiltinFunc(1, func(interp *Interpreter, _ *LoxInstance, args ...interface{}) interface{} {
+		return typeName(args[0])
+	}))
+
+	// classOf(value) returns the class of an instance or of a string, nil for other values.
+	i.global.Define("classOf", NewBuiltinFunc(1, func(interp *Interpreter, _ *LoxInstance, args ...interface{}) interface{} {
+		if class := classOf(args[0]); class != nil {
+			return class
+		}
+		return nil
+	}))
+}
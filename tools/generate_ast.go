@@ -24,15 +24,21 @@ func main() {
 		"Assign		: Name *Token, Operator *Token, Value Expr",
 		"Array		: Elements []Expr",
 		"Binary		: Left Expr, Operator *Token, Right Expr",
-		"Call		: Callee Expr, Paren *Token, Arguments []Expr",
-		"Get		: Object Expr, Name *Token",
+		"Call		: Callee Expr, Paren *Token, Arguments []Expr, Optional bool",
+		"Chain		: Expression Expr", // a chain of calls, gets & subscripts, some of them optional.
+		"Conditional	: Condition Expr, ThenBranch Expr, ElseBranch Expr",
+		"Destructure	: Pattern Pattern, Operator *Token, Value Expr", // patterns are declared in match.go.
+		"Get		: Object Expr, Name *Token, Optional bool",
 		"Grouping	: Expression Expr",
 		"Lambda		: LambdaFunc *Function",
 		"Literal	: Value interface{}",
 		"Logical	: Left Expr, Operator *Token, Right Expr",
-		"Set		: Object Expr, Name *Token, Value Expr",
-		"Subscript	: Object Expr, Key Expr, Bracket *Token", // Bracket is reserved for error reporting.
+		"Match		: Keyword *Token, Subject Expr, Cases []*Case, Statement bool", // cases are declared in match.go.
+		"Set		: Object Expr, Name *Token, Operator *Token, Value Expr",
+		"Spread		: Ellipsis *Token, Expression Expr",                       // spreads an array into the arguments of a call or the elements of an array.
+		"Subscript	: Object Expr, Key Expr, Bracket *Token, Optional bool", // Bracket is reserved for error reporting.
 		"Super		: Keyword *Token, Method *Token",
+		"SuperSet	: Keyword *Token, Name *Token, Operator *Token, Value Expr",
 		"This		: Keyword *Token",
 		"Unary		: Operator *Token, Right Expr",
 		"Variable	: Name *Token",
@@ -40,13 +46,17 @@ func main() {
 
 	defineAst(out, "Stmt", []string{
 		"Block		: Stmts []Stmt",
-		"Class		: Name *Token, Super *Variable, Statics []*Function, Methods []*Function, Getters []*Function, Setters []*Function",
+		"Class		: Name *Token, Super *Variable, Traits []*Variable, Interfaces []*Variable, Statics []*Function, Methods []*Function, Getters []*Function, Setters []*Function, StaticGetters []*Function, StaticSetters []*Function, Initializers []Stmt",
 		"Control	: Keyword *Token, CtrlType ControlType, Value Expr",
-		"Function	: Name *Token, Params []*Token, Body []Stmt",
+		"Enum		: Name *Token, Members []*Token, Methods []*Function, Getters []*Function, Setters []*Function",
+		"Function	: Name *Token, Params []*Token, Defaults []Expr, Rest *Token, Body []Stmt", // Defaults are nil for the parameters without.
 		"Expression	: Expression Expr",
 		"If			: Condition Expr, ThenBranch Stmt, ElseBranch Stmt",
+		"Interface	: Name *Token, Methods []*Function, Getters []*Function", // members have no body.
 		"Print		: Expression Expr",
-		"Var		: Name *Token, Initializer Expr",
+		"Trait		: Name *Token, Methods []*Function, Getters []*Function, Setters []*Function",
+		"Var		: Name *Token, Initializer Expr, Constant bool",
+		"VarDestructure	: Keyword *Token, Pattern Pattern, Initializer Expr, Constant bool",
 		"VarList	: stmts []*Var",
 		"While		: Condition Expr, Body Stmt",
 	})